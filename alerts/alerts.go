@@ -1,29 +1,132 @@
 package alerts
 
 import (
+	"context"
 	"io"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/Miskamyasa/utils/internal/requestid"
+
 	"github.com/rs/zerolog"
 )
 
+// Level is the severity of an alert.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarning
+	LevelError
+	LevelCritical
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarning:
+		return "warning"
+	case LevelError:
+		return "error"
+	case LevelCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+func (l Level) zerologLevel() zerolog.Level {
+	switch l {
+	case LevelDebug:
+		return zerolog.DebugLevel
+	case LevelInfo:
+		return zerolog.InfoLevel
+	case LevelWarning:
+		return zerolog.WarnLevel
+	case LevelCritical:
+		return zerolog.FatalLevel
+	default:
+		return zerolog.ErrorLevel
+	}
+}
+
 var once sync.Once
 var log zerolog.Logger
 
+var minLevel atomic.Int64
+
+// SetMinLevel drops alerts below level, e.g. debug alerts in production.
+func SetMinLevel(level Level) {
+	minLevel.Store(int64(level))
+}
+
+// Send sends an alert at LevelError.
 func Send(msg string, err error) {
-	logger := CreateLogger().With().Str("component", "alerts").Logger()
-	if err != nil {
-		logger.Err(err).Msg(msg)
+	SendWithLevel(LevelError, msg, err)
+}
+
+func SendWithLevel(level Level, msg string, err error) {
+	if int64(level) < minLevel.Load() {
 		return
 	}
-	logger.Info().Msg(msg)
-	// TODO: send admin notification
+	deliver(context.Background(), Alert{
+		Level: level,
+		Title: msg,
+		Err:   err,
+		Time:  time.Now(),
+	})
+}
+
+// SendContext sends an alert at LevelError carrying fields and the request
+// ID found in ctx, so it can be correlated with the request that caused it.
+func SendContext(ctx context.Context, msg string, err error, fields map[string]any) {
+	if int64(LevelError) < minLevel.Load() {
+		return
+	}
+	all := make(map[string]any, len(fields)+1)
+	for name, value := range fields {
+		all[name] = value
+	}
+	if id := requestid.FromContext(ctx); id != "" {
+		all["request_id"] = id
+	}
+	// The alert must still go out when the request has been cancelled.
+	deliver(context.WithoutCancel(ctx), Alert{
+		Level:  LevelError,
+		Title:  msg,
+		Err:    err,
+		Time:   time.Now(),
+		Fields: all,
+	})
+}
+
+// deliver hands the alert to the current sink. A sink failure can only be
+// logged, since alerting about it would go through the same sink.
+func deliver(ctx context.Context, alert Alert) {
+	err := currentSink().Send(ctx, alert)
+	if err != nil {
+		logger := CreateLogger().With().Str("component", "alerts").Logger()
+		logger.Err(err).Str("alert", alert.Title).Msg("Failed to deliver alert")
+	}
+}
+
+// Flush waits for queued alerts to be delivered if the current sink buffers
+// them, like AsyncSink does.
+func Flush() {
+	if s, ok := currentSink().(interface{ Flush() }); ok {
+		s.Flush()
+	}
 }
 
 func Fatal(msg string, err error) {
-	Send(msg, err)
+	SendWithLevel(LevelCritical, msg, err)
+	Flush()
 	os.Exit(1)
 }
 
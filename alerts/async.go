@@ -0,0 +1,102 @@
+package alerts
+
+import (
+	"context"
+	"sync"
+)
+
+// AsyncSink queues alerts in a buffered channel and delivers them to another
+// sink from a background goroutine, so a slow destination never blocks the
+// caller. When the buffer is full, alerts are dropped and logged.
+type AsyncSink struct {
+	next  AlertSink
+	queue chan Alert
+	done  chan struct{}
+
+	// pending counts queued and in-flight alerts. A WaitGroup cannot be
+	// used, since Send may add to it while Flush is waiting.
+	pendingMu sync.Mutex
+	drained   *sync.Cond
+	pending   int
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func NewAsyncSink(next AlertSink, bufferSize int) *AsyncSink {
+	s := &AsyncSink{
+		next:  next,
+		queue: make(chan Alert, bufferSize),
+		done:  make(chan struct{}),
+	}
+	s.drained = sync.NewCond(&s.pendingMu)
+	go s.run()
+	return s
+}
+
+func (s *AsyncSink) Send(_ context.Context, alert Alert) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		logDropped(alert, "alert sink is closed")
+		return nil
+	}
+
+	s.addPending(1)
+	select {
+	case s.queue <- alert:
+		return nil
+	default:
+		s.addPending(-1)
+		logDropped(alert, "alert buffer is full")
+		return nil
+	}
+}
+
+// Flush blocks until every queued alert has been delivered.
+func (s *AsyncSink) Flush() {
+	s.pendingMu.Lock()
+	for s.pending > 0 {
+		s.drained.Wait()
+	}
+	s.pendingMu.Unlock()
+}
+
+func (s *AsyncSink) addPending(delta int) {
+	s.pendingMu.Lock()
+	s.pending += delta
+	if s.pending == 0 {
+		s.drained.Broadcast()
+	}
+	s.pendingMu.Unlock()
+}
+
+// Close stops accepting alerts and waits until the queued ones have been
+// delivered.
+func (s *AsyncSink) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+	<-s.done
+	return nil
+}
+
+func (s *AsyncSink) run() {
+	defer close(s.done)
+	for alert := range s.queue {
+		err := s.next.Send(context.Background(), alert)
+		if err != nil {
+			logger := CreateLogger().With().Str("component", "alerts").Logger()
+			logger.Err(err).Str("alert", alert.Title).Msg("Failed to deliver alert")
+		}
+		s.addPending(-1)
+	}
+}
+
+func logDropped(alert Alert, reason string) {
+	logger := CreateLogger().With().Str("component", "alerts").Logger()
+	logger.Warn().Str("alert", alert.Title).Msg("Dropped alert: " + reason)
+}
@@ -0,0 +1,83 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+type dedupEntry struct {
+	suppressed int
+	// last is the most recent suppressed alert, reported in the summary.
+	last Alert
+}
+
+type dedupSink struct {
+	next   AlertSink
+	window time.Duration
+
+	mu      sync.Mutex
+	entries map[uint64]*dedupEntry
+}
+
+// NewDedupSink returns a sink that forwards an alert to next only once per
+// window for the same title and error. When the window ends, a summary of the
+// suppressed alerts is sent, e.g. "(repeated 412 times in 5m0s)".
+func NewDedupSink(next AlertSink, window time.Duration) AlertSink {
+	return &dedupSink{
+		next:    next,
+		window:  window,
+		entries: make(map[uint64]*dedupEntry),
+	}
+}
+
+func (d *dedupSink) Send(ctx context.Context, alert Alert) error {
+	key := alertKey(alert)
+
+	d.mu.Lock()
+	if entry, ok := d.entries[key]; ok {
+		entry.suppressed++
+		entry.last = alert
+		d.mu.Unlock()
+		return nil
+	}
+	d.entries[key] = &dedupEntry{}
+	d.mu.Unlock()
+
+	time.AfterFunc(d.window, func() {
+		d.summarize(key)
+	})
+	return d.next.Send(ctx, alert)
+}
+
+// summarize ends the window of key and reports the alerts it suppressed.
+func (d *dedupSink) summarize(key uint64) {
+	d.mu.Lock()
+	entry := d.entries[key]
+	delete(d.entries, key)
+	d.mu.Unlock()
+	if entry == nil || entry.suppressed == 0 {
+		return
+	}
+
+	alert := entry.last
+	alert.Title += fmt.Sprintf(" (repeated %d times in %s)", entry.suppressed, d.window)
+	err := d.next.Send(context.Background(), alert)
+	if err != nil {
+		logger := CreateLogger().With().Str("component", "alerts").Logger()
+		logger.Err(err).Str("alert", alert.Title).Msg("Failed to deliver alert")
+	}
+}
+
+// alertKey identifies alerts considered identical.
+func alertKey(alert Alert) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(alert.Title))
+	h.Write([]byte{0})
+	if alert.Err != nil {
+		h.Write([]byte(alert.Err.Error()))
+	}
+	return h.Sum64()
+}
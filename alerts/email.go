@@ -0,0 +1,104 @@
+package alerts
+
+import (
+	"context"
+	"crypto/tls"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// EmailSink sends alerts by email through an SMTP server.
+type EmailSink struct {
+	// Addr is the host:port of the SMTP server.
+	Addr string
+	// Auth can be nil for servers that do not require authentication.
+	Auth smtp.Auth
+	From string
+	To   []string
+}
+
+// Send delivers the alert within the deadline of ctx, or 10 seconds if it
+// has none.
+func (s EmailSink) Send(ctx context.Context, alert Alert) error {
+	// The body carries the full text, which can span many lines, e.g. with
+	// a stack trace; the subject only names the alert.
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(alert.Title)
+	msg := "From: " + s.From + "\r\n" +
+		"To: " + strings.Join(s.To, ", ") + "\r\n" +
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" +
+		alert.Text() + "\r\n"
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sendTimeout)
+		defer cancel()
+	}
+	return s.sendMail(ctx, []byte(msg))
+}
+
+// sendMail does what smtp.SendMail does, but gives up when ctx is done.
+func (s EmailSink) sendMail(ctx context.Context, msg []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	// Closing the connection unblocks any pending read or write.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	host, _, _ := net.SplitHostPort(s.Addr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		err = c.StartTLS(&tls.Config{ServerName: host})
+		if err != nil {
+			return err
+		}
+	}
+	if s.Auth != nil {
+		if ok, _ := c.Extension("AUTH"); ok {
+			err = c.Auth(s.Auth)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	err = c.Mail(s.From)
+	if err != nil {
+		return err
+	}
+	for _, to := range s.To {
+		err = c.Rcpt(to)
+		if err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(msg)
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+	return c.Quit()
+}
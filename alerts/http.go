@@ -0,0 +1,44 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// sendTimeout bounds how long a sink may take to deliver one alert, so a
+// hung service does not block the callers of Send.
+const sendTimeout = 10 * time.Second
+
+var defaultClient = &http.Client{Timeout: sendTimeout}
+
+// postJSON posts payload to url and fails on a non-2xx response.
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	if client == nil {
+		client = defaultClient
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("alerts: %s responded with %s", req.URL.Host, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package alerts
+
+import (
+	"context"
+	"sync"
+)
+
+// NopSink discards every alert.
+type NopSink struct{}
+
+func (NopSink) Send(context.Context, Alert) error {
+	return nil
+}
+
+// RecordingSink keeps alerts in memory so tests can assert what was sent
+// without any network I/O:
+//
+//	rec := &alerts.RecordingSink{}
+//	defer alerts.SetSink(alerts.SetSink(rec))
+type RecordingSink struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func (r *RecordingSink) Send(_ context.Context, alert Alert) error {
+	r.mu.Lock()
+	r.alerts = append(r.alerts, alert)
+	r.mu.Unlock()
+	return nil
+}
+
+// Alerts returns a copy of the alerts recorded so far.
+func (r *RecordingSink) Alerts() []Alert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Alert(nil), r.alerts...)
+}
+
+func (r *RecordingSink) Reset() {
+	r.mu.Lock()
+	r.alerts = nil
+	r.mu.Unlock()
+}
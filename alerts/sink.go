@@ -0,0 +1,102 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Alert is what gets delivered to an AlertSink.
+type Alert struct {
+	Level Level
+	Title string
+	Err   error
+	Time  time.Time
+	// Fields holds structured metadata such as the request ID or path.
+	Fields map[string]any
+}
+
+// Text renders the alert as a single human readable message.
+func (a Alert) Text() string {
+	var sb strings.Builder
+	sb.WriteString("[" + strings.ToUpper(a.Level.String()) + "] " + a.Title)
+	if a.Err != nil {
+		sb.WriteString(": " + a.Err.Error())
+	}
+	names := make([]string, 0, len(a.Fields))
+	for name := range a.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&sb, "\n%s: %v", name, a.Fields[name])
+	}
+	return sb.String()
+}
+
+// AlertSink delivers alerts to a destination such as a log, a chat or email.
+type AlertSink interface {
+	Send(ctx context.Context, alert Alert) error
+}
+
+var (
+	sinkMu sync.RWMutex
+	sink   AlertSink = LogSink{}
+)
+
+// SetSink replaces the sink used by Send, SendWithLevel and SendContext and
+// returns the previous one, so tests can restore it. The default is LogSink;
+// use MultiSink to keep logging while also notifying elsewhere.
+func SetSink(s AlertSink) AlertSink {
+	sinkMu.Lock()
+	defer sinkMu.Unlock()
+	previous := sink
+	sink = s
+	return previous
+}
+
+func currentSink() AlertSink {
+	sinkMu.RLock()
+	defer sinkMu.RUnlock()
+	return sink
+}
+
+// LogSink writes alerts with the shared service logger.
+type LogSink struct{}
+
+func (LogSink) Send(_ context.Context, alert Alert) error {
+	logger := CreateLogger().With().Str("component", "alerts").Logger()
+	// WithLevel logs at the fatal level for critical alerts without exiting.
+	event := logger.WithLevel(alert.Level.zerologLevel()).Str("severity", alert.Level.String())
+	if alert.Err != nil {
+		event = event.Err(alert.Err)
+	}
+	if len(alert.Fields) > 0 {
+		event = event.Fields(alert.Fields)
+	}
+	event.Msg(alert.Title)
+	return nil
+}
+
+type multiSink []AlertSink
+
+// MultiSink returns a sink delivering every alert to all of sinks. It keeps
+// going when one of them fails and returns all errors joined.
+func MultiSink(sinks ...AlertSink) AlertSink {
+	return multiSink(sinks)
+}
+
+func (m multiSink) Send(ctx context.Context, alert Alert) error {
+	var errs []error
+	for _, s := range m {
+		err := s.Send(ctx, alert)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,19 @@
+package alerts
+
+import (
+	"context"
+	"net/http"
+)
+
+// SlackSink posts alerts to a Slack incoming webhook.
+type SlackSink struct {
+	WebhookURL string
+	// Client defaults to a client with a 10 second timeout.
+	Client *http.Client
+}
+
+func (s SlackSink) Send(ctx context.Context, alert Alert) error {
+	return postJSON(ctx, s.Client, s.WebhookURL, map[string]string{
+		"text": alert.Text(),
+	})
+}
@@ -0,0 +1,43 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// telegramMaxLength is the longest message text Telegram accepts.
+const telegramMaxLength = 4096
+
+// TelegramSink sends alerts as messages from a Telegram bot.
+type TelegramSink struct {
+	BotToken string
+	ChatID   string
+	// Client defaults to a client with a 10 second timeout.
+	Client *http.Client
+}
+
+func (s TelegramSink) Send(ctx context.Context, alert Alert) error {
+	url := "https://api.telegram.org/bot" + s.BotToken + "/sendMessage"
+	err := postJSON(ctx, s.Client, url, map[string]string{
+		"chat_id": s.ChatID,
+		"text":    truncateText(alert.Text(), telegramMaxLength),
+	})
+	// Transport errors quote the URL, which contains the token.
+	if err != nil && s.BotToken != "" && strings.Contains(err.Error(), s.BotToken) {
+		return errors.New(strings.ReplaceAll(err.Error(), s.BotToken, "<redacted>"))
+	}
+	return err
+}
+
+// truncateText cuts text to at most limit characters, marking the cut.
+func truncateText(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	const marker = "\n… (truncated)"
+	runes := []rune(text)
+	return string(runes[:limit-utf8.RuneCountInString(marker)]) + marker
+}
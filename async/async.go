@@ -1,34 +1,242 @@
 package async
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
 
+// ErrTimeout is returned by AwaitResult when a future created by
+// ExecAsyncWithTimeout did not finish in time.
+var ErrTimeout = errors.New("async: timeout")
+
+// Future is the untyped future of the pre-generics API. The task returned by
+// ExecAsync for a func() interface{} implements it, so existing code such as
+// var f async.Future = async.ExecAsync(fn) keeps compiling.
 type Future interface {
 	Await() interface{}
 }
 
-type future struct {
-	await func(ctx context.Context) interface{}
+// Task holds the result of a function started by ExecAsync.
+type Task[T any] struct {
+	done   chan struct{}
+	once   sync.Once
+	result T
+	err    error
+
+	// start, when set, is run on the first wait. It is used by lazy futures.
+	start     func()
+	startOnce sync.Once
+}
+
+// AnyFuture is the task returned by ExecAsync for a func() interface{}. It
+// implements Future.
+type AnyFuture = Task[interface{}]
+
+var _ Future = (*AnyFuture)(nil)
+
+func newTask[T any]() *Task[T] {
+	return &Task[T]{done: make(chan struct{})}
+}
+
+func (f *Task[T]) resolve(result T, err error) {
+	f.once.Do(func() {
+		f.result = result
+		f.err = err
+		close(f.done)
+	})
+}
+
+// wait triggers a lazy future and returns the channel closed on resolution.
+func (f *Task[T]) wait() <-chan struct{} {
+	if f.start != nil {
+		f.startOnce.Do(f.start)
+	}
+	return f.done
+}
+
+// run calls fn and resolves f with its result, turning a panic into an error
+// that carries the stack trace of the panicking goroutine.
+func (f *Task[T]) run(fn func() (T, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+			f.resolve(zero, fmt.Errorf("async: panic: %v\n%s", r, debug.Stack()))
+		}
+	}()
+	f.resolve(fn())
+}
+
+// Await blocks until the function has returned and gives back its result.
+// The function runs only once no matter how many times Await is called.
+func (f *Task[T]) Await() T {
+	<-f.wait()
+	return f.result
+}
+
+// AwaitResult is like Await but also reports the error the function failed
+// with. A panic in the function is returned as an error.
+func (f *Task[T]) AwaitResult() (T, error) {
+	<-f.wait()
+	return f.result, f.err
 }
 
-func (f future) Await() interface{} {
-	return f.await(context.Background())
+// AwaitOr is like Await but returns defaultVal if the function failed or
+// panicked. A zero or nil result of a successful function is returned as is.
+func (f *Task[T]) AwaitOr(defaultVal T) T {
+	<-f.wait()
+	if f.err != nil {
+		return defaultVal
+	}
+	return f.result
+}
+
+// AwaitContext is like AwaitResult but gives up with ctx.Err() once ctx is done.
+// An already available result is returned even if ctx is done. The function
+// keeps running after the wait is abandoned and its result stays cached.
+func (f *Task[T]) AwaitContext(ctx context.Context) (T, error) {
+	done := f.wait()
+	select {
+	case <-done:
+		return f.result, f.err
+	default:
+	}
+	select {
+	case <-done:
+		return f.result, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
 }
 
-func ExecAsync(f func() interface{}) Future {
-	var result interface{}
-	c := make(chan struct{})
+// IsDone reports whether the result is available without blocking. It does
+// not start a lazy future.
+func (f *Task[T]) IsDone() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// OnComplete calls cb with the result once f has resolved, on its own
+// goroutine, even if f resolved before OnComplete was called. Every
+// registered callback is called exactly once. Like Await, it starts a lazy
+// future.
+func (f *Task[T]) OnComplete(cb func(result T)) {
+	done := f.wait()
 	go func() {
-		defer close(c)
-		result = f()
+		<-done
+		cb(f.result)
 	}()
-	return future{
-		await: func(ctx context.Context) interface{} {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-c:
-				return result
+}
+
+// Then returns a future resolving to fn applied to the result of f. fn runs
+// once on its own goroutine after f resolves. If f failed, fn is skipped and
+// the returned future carries the same error.
+func (f *Task[T]) Then(fn func(T) T) *Task[T] {
+	next := newTask[T]()
+	go next.run(func() (T, error) {
+		result, err := f.AwaitResult()
+		if err != nil {
+			return result, err
+		}
+		return fn(result), nil
+	})
+	return next
+}
+
+// Map returns a lazy future resolving to fn applied to the result of f. fn
+// runs once, when the returned future is first awaited. If f failed, fn is
+// skipped and the returned future carries the same error.
+func Map[T, U any](f *Task[T], fn func(T) U) *Task[U] {
+	mapped := newTask[U]()
+	mapped.start = func() {
+		go mapped.run(func() (U, error) {
+			result, err := f.AwaitResult()
+			if err != nil {
+				var zero U
+				return zero, err
 			}
-		},
+			return fn(result), nil
+		})
 	}
+	return mapped
+}
+
+// ExecAsync runs fn on its own goroutine and returns a task for its
+// result. A panic in fn does not crash the program; AwaitResult reports it
+// as an error.
+func ExecAsync[T any](fn func() T) *Task[T] {
+	f := newTask[T]()
+	go f.run(func() (T, error) {
+		return fn(), nil
+	})
+	return f
+}
+
+// ExecAsyncErr runs fn on its own goroutine like ExecAsync, but keeps its
+// error apart from its value: AwaitResult returns both, so a value that
+// happens to be an error is never mistaken for a failure.
+func ExecAsyncErr[T any](fn func() (T, error)) *Task[T] {
+	f := newTask[T]()
+	go f.run(fn)
+	return f
+}
+
+// ExecAsyncContext runs fn on its own goroutine with ctx, e.g. the request
+// context. The future resolves with ctx.Err() as soon as ctx is done, unless
+// fn has already returned; fn is expected to watch ctx and stop early.
+func ExecAsyncContext[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) *Task[T] {
+	inner := ExecAsyncErr(func() (T, error) {
+		return fn(ctx)
+	})
+	f := newTask[T]()
+	go func() {
+		select {
+		case <-inner.done:
+		case <-ctx.Done():
+			select {
+			case <-inner.done:
+			default:
+				var zero T
+				f.resolve(zero, ctx.Err())
+				return
+			}
+		}
+		f.resolve(inner.result, inner.err)
+	}()
+	return f
+}
+
+// ExecAsyncWithTimeout is like ExecAsync but resolves the future with
+// ErrTimeout if fn has not returned within d. A result that is already
+// computed when the timer fires always wins over the timeout. The watcher
+// goroutine exits as soon as either happens; fn itself cannot be stopped and
+// its late result is discarded.
+func ExecAsyncWithTimeout[T any](fn func() T, d time.Duration) *Task[T] {
+	inner := ExecAsync(fn)
+	f := newTask[T]()
+	go func() {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-inner.done:
+			f.resolve(inner.result, inner.err)
+		case <-timer.C:
+			select {
+			case <-inner.done:
+				f.resolve(inner.result, inner.err)
+			default:
+				var zero T
+				f.resolve(zero, ErrTimeout)
+			}
+		}
+	}()
+	return f
 }
@@ -0,0 +1,39 @@
+package async
+
+import "reflect"
+
+// AwaitAll blocks until every future has resolved and returns their results
+// in the order of the inputs. The futures already run concurrently, so the
+// total wait is bounded by the slowest one.
+func AwaitAll[T any](futures ...*Task[T]) []T {
+	// Start every lazy future before blocking, so they run concurrently.
+	done := make([]<-chan struct{}, len(futures))
+	for i, f := range futures {
+		done[i] = f.wait()
+	}
+	results := make([]T, len(futures))
+	for i, f := range futures {
+		<-done[i]
+		results[i] = f.result
+	}
+	return results
+}
+
+// NoFuture is the index AwaitAny returns when it is given no futures.
+const NoFuture = -1
+
+// AwaitAny blocks until the first of the futures resolves and returns its
+// result together with its index. Called without futures it returns the zero
+// value and NoFuture right away.
+func AwaitAny[T any](futures ...*Task[T]) (T, int) {
+	if len(futures) == 0 {
+		var zero T
+		return zero, NoFuture
+	}
+	cases := make([]reflect.SelectCase, len(futures))
+	for i, f := range futures {
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(f.wait())}
+	}
+	i, _, _ := reflect.Select(cases)
+	return futures[i].result, i
+}
@@ -0,0 +1,52 @@
+package async
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// ExecAsyncBatch applies fn to every item concurrently and returns the
+// results in input order. At most maxConcurrency calls run at once; a value of
+// 0 or less runs all of them at the same time. If fn panics, the remaining
+// calls still finish and the first panic is raised again on the caller's
+// goroutine, with the stack trace of the call that panicked.
+func ExecAsyncBatch[T, U any](items []T, fn func(T) U, maxConcurrency int) []U {
+	results := make([]U, len(items))
+	if maxConcurrency <= 0 || maxConcurrency > len(items) {
+		maxConcurrency = len(items)
+	}
+
+	var (
+		wg         sync.WaitGroup
+		panicMu    sync.Mutex
+		firstPanic error
+	)
+	sem := make(chan struct{}, maxConcurrency)
+	for i, item := range items {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			defer func() {
+				if r := recover(); r != nil {
+					err := fmt.Errorf("async: panic: %v\n%s", r, debug.Stack())
+					panicMu.Lock()
+					if firstPanic == nil {
+						firstPanic = err
+					}
+					panicMu.Unlock()
+				}
+			}()
+			results[i] = fn(item)
+		}()
+	}
+	wg.Wait()
+	if firstPanic != nil {
+		panic(firstPanic)
+	}
+	return results
+}
@@ -0,0 +1,23 @@
+package async
+
+import (
+	"sync"
+	"time"
+)
+
+// Debounce returns a function that calls fn once d has passed without
+// another call. Each call cancels the pending one and restarts the wait. fn
+// runs on its own goroutine, and the returned function is safe for
+// concurrent use.
+func Debounce(d time.Duration, fn func()) func() {
+	var mu sync.Mutex
+	var timer *time.Timer
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(d, fn)
+	}
+}
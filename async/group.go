@@ -0,0 +1,59 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// TaskGroup tracks fire-and-forget goroutines so they can be drained, e.g.
+// during a graceful shutdown. The zero value is ready to use.
+type TaskGroup struct {
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	panics []error
+}
+
+// Go runs fn on its own goroutine. A panic in fn is recovered and reported
+// by Wait instead of crashing the process.
+func (g *TaskGroup) Go(fn func()) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				err := fmt.Errorf("async: panic: %v\n%s", r, debug.Stack())
+				g.mu.Lock()
+				g.panics = append(g.panics, err)
+				g.mu.Unlock()
+			}
+		}()
+		fn()
+	}()
+}
+
+// Wait blocks until every function started with Go has returned and returns
+// the panics recovered from them joined into one error, or nil.
+func (g *TaskGroup) Wait() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return errors.Join(g.panics...)
+}
+
+// WaitContext is like Wait but gives up with ctx.Err() once ctx is done. The
+// functions keep running after the wait is abandoned.
+func (g *TaskGroup) WaitContext(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- g.Wait()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,121 @@
+package async
+
+import (
+	"container/heap"
+	"errors"
+	"sync"
+)
+
+// ErrPoolClosed is returned by AwaitResult for work submitted after Shutdown.
+var ErrPoolClosed = errors.New("async: pool is shut down")
+
+type task struct {
+	fn       func() interface{}
+	future   *AnyFuture
+	priority int
+	// seq keeps tasks of equal priority in submission order.
+	seq uint64
+}
+
+// taskQueue is a heap of tasks, highest priority first.
+type taskQueue []task
+
+func (q taskQueue) Len() int { return len(q) }
+
+func (q taskQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q taskQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *taskQueue) Push(x interface{}) { *q = append(*q, x.(task)) }
+
+func (q *taskQueue) Pop() interface{} {
+	old := *q
+	t := old[len(old)-1]
+	old[len(old)-1] = task{}
+	*q = old[:len(old)-1]
+	return t
+}
+
+// Pool runs submitted functions on a fixed number of worker goroutines.
+type Pool struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  taskQueue
+	seq    uint64
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewPool starts a pool that never runs more than maxConcurrent functions at
+// the same time. A maxConcurrent below 1 is treated as 1.
+func NewPool(maxConcurrent int) *Pool {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	p := &Pool{}
+	p.cond = sync.NewCond(&p.mu)
+	p.wg.Add(maxConcurrent)
+	for i := 0; i < maxConcurrent; i++ {
+		go p.worker()
+	}
+	return p
+}
+
+// Submit queues fn and returns a future for its result. After Shutdown the
+// returned future resolves immediately with ErrPoolClosed.
+func (p *Pool) Submit(fn func() interface{}) *AnyFuture {
+	return p.SubmitPriority(fn, 0)
+}
+
+// SubmitPriority is like Submit, but fn runs before queued tasks of a lower
+// priority. Tasks of equal priority run in the order they were submitted;
+// Submit uses priority 0.
+func (p *Pool) SubmitPriority(fn func() interface{}, priority int) *AnyFuture {
+	f := newTask[interface{}]()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		f.resolve(nil, ErrPoolClosed)
+		return f
+	}
+	p.seq++
+	heap.Push(&p.queue, task{fn: fn, future: f, priority: priority, seq: p.seq})
+	p.cond.Signal()
+	return f
+}
+
+// Shutdown stops accepting new work and waits until every queued and running
+// task has finished.
+func (p *Pool) Shutdown() {
+	p.mu.Lock()
+	p.closed = true
+	p.cond.Broadcast()
+	p.mu.Unlock()
+	p.wg.Wait()
+}
+
+func (p *Pool) worker() {
+	defer p.wg.Done()
+	for {
+		p.mu.Lock()
+		for len(p.queue) == 0 && !p.closed {
+			p.cond.Wait()
+		}
+		if len(p.queue) == 0 {
+			p.mu.Unlock()
+			return
+		}
+		t := heap.Pop(&p.queue).(task)
+		p.mu.Unlock()
+
+		t.future.run(func() (interface{}, error) {
+			return t.fn(), nil
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package async
+
+import "time"
+
+// MaxRetryBackoff caps the delay between attempts of ExecAsyncRetry.
+var MaxRetryBackoff = 30 * time.Second
+
+// ExecAsyncRetry runs fn until it succeeds or has been called attempts times.
+// The delay between failures starts at backoff and doubles each time, up to
+// MaxRetryBackoff. AwaitResult returns the first successful result or the
+// error of the last attempt.
+func ExecAsyncRetry[T any](fn func() (T, error), attempts int, backoff time.Duration) *Task[T] {
+	if attempts < 1 {
+		attempts = 1
+	}
+	f := newTask[T]()
+	go f.run(func() (T, error) {
+		delay := backoff
+		for i := 1; ; i++ {
+			result, err := fn()
+			if err == nil || i == attempts {
+				return result, err
+			}
+			time.Sleep(delay)
+			delay *= 2
+			if delay > MaxRetryBackoff {
+				delay = MaxRetryBackoff
+			}
+		}
+	})
+	return f
+}
@@ -0,0 +1,39 @@
+package async
+
+import (
+	"sync"
+	"time"
+)
+
+// Throttle returns a function that starts fn at most once per interval d. The
+// first call runs fn right away; calls made during the following interval
+// are coalesced into a single call at its end, so the last request is never
+// lost. fn runs on its own goroutine, and the returned function is safe for
+// concurrent use.
+func Throttle(d time.Duration, fn func()) func() {
+	var mu sync.Mutex
+	var last time.Time
+	pending := false
+	run := func() {
+		mu.Lock()
+		pending = false
+		last = time.Now()
+		mu.Unlock()
+		fn()
+	}
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if pending {
+			return
+		}
+		wait := d - time.Since(last)
+		if wait <= 0 {
+			last = time.Now()
+			go fn()
+			return
+		}
+		pending = true
+		time.AfterFunc(wait, run)
+	}
+}
@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"strconv"
 	"time"
@@ -13,7 +14,39 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-var instance *cache.Cache
+// ErrCacheMiss is returned by Get when a key is missing or has expired.
+var ErrCacheMiss = errors.New("cache: key is missing")
+
+// Cache is implemented by every cache backend. Values are stored as JSON, so
+// dest in Get must be a pointer to a type value can be decoded into.
+type Cache interface {
+	Get(key string, dest interface{}) error
+	// Set stores value under key for ttl. A ttl of zero or less never
+	// expires.
+	Set(key string, value interface{}, ttl time.Duration) error
+	Delete(key string) error
+	// GetMany returns the values found for keys, decoded into interface{}
+	// values. Missing keys are absent from the result.
+	GetMany(keys []string) (map[string]interface{}, error)
+	SetMany(entries map[string]CacheItem) error
+	// Clear removes every key of the cache. Backends shared with other
+	// applications only remove their own keys.
+	Clear() error
+}
+
+// CacheItem is a value with its TTL, as stored by SetMany.
+type CacheItem struct {
+	Value interface{}
+	TTL   time.Duration
+}
+
+// prefixClearer is implemented by backends that can remove all keys starting
+// with a prefix. It is what allows WithNamespace to clear a namespace.
+type prefixClearer interface {
+	clearPrefix(prefix string) error
+}
+
+var instance Cache
 
 var cacheCtx = context.Background()
 
@@ -31,30 +64,86 @@ func InitCache() *redis.Client {
 		LFUSize = 1000
 	}
 
-	instance = cache.New(&cache.Options{
-		Redis:      client,
-		LocalCache: cache.NewTinyLFU(LFUSize, time.Second),
-	})
+	instance = &tieredCache{
+		cache: cache.New(&cache.Options{
+			Redis:      client,
+			LocalCache: cache.NewTinyLFU(LFUSize, time.Second),
+		}),
+		client: client,
+		prefix: os.Getenv("CACHE_PREFIX"),
+	}
 
 	return client
 }
 
+// Default returns the cache used by the package level functions.
+func Default() Cache {
+	return instance
+}
+
+// SetDefault replaces the cache used by the package level functions, e.g.
+// with an in-memory cache in tests.
+func SetDefault(c Cache) {
+	instance = c
+}
+
 func CreateDuration(seconds int) time.Duration {
 	return time.Duration(seconds) * time.Second
 }
 
+// Enabled reports whether the package level functions use the cache. They
+// are no-ops when ENV is "development".
+func Enabled() bool {
+	return os.Getenv("ENV") != "development"
+}
+
 func GetCache[T any](key string, payload *T) error {
-	if os.Getenv("ENV") == "development" {
+	if !Enabled() {
 		return nil
 	}
+	return instance.Get(key, payload)
+}
+
+func SetCache[T any](key string, payload T, TTL time.Duration) error {
+	if !Enabled() {
+		return nil
+	}
+	return instance.Set(key, payload, TTL)
+}
+
+func DeleteCache(key string) error {
+	if !Enabled() {
+		return nil
+	}
+	return instance.Delete(key)
+}
+
+func ClearCache() error {
+	if !Enabled() {
+		return nil
+	}
+	return instance.Clear()
+}
 
+// tieredCache is the default backend created by InitCache: a local TinyLFU
+// cache in front of Redis. Keys are prefixed with CACHE_PREFIX.
+type tieredCache struct {
+	cache  *cache.Cache
+	client *redis.Client
+	prefix string
+}
+
+func (c *tieredCache) Get(key string, dest interface{}) error {
 	var jsonPayload []byte
-	err := instance.Get(cacheCtx, key, &jsonPayload)
+	err := c.cache.Get(cacheCtx, c.prefix+key, &jsonPayload)
+	if err == cache.ErrCacheMiss {
+		return ErrCacheMiss
+	}
 	if err != nil {
 		return err
 	}
 
-	err = json.Unmarshal(jsonPayload, payload)
+	err = json.Unmarshal(jsonPayload, dest)
 	if err != nil {
 		return err
 	}
@@ -62,21 +151,25 @@ func GetCache[T any](key string, payload *T) error {
 	return nil
 }
 
-func SetCache[T any](key string, payload T, TTL time.Duration) error {
-	if os.Getenv("ENV") == "development" {
-		return nil
-	}
-
-	jsonPayload, err := json.Marshal(payload)
+func (c *tieredCache) Set(key string, value interface{}, ttl time.Duration) error {
+	jsonPayload, err := json.Marshal(value)
 	if err != nil {
 		return err
 	}
 
-	err = instance.Set(&cache.Item{
+	// go-redis/cache stores a zero TTL for an hour and a sub-second one
+	// for an hour too; only a negative TTL means no expiry.
+	switch {
+	case ttl <= 0:
+		ttl = -1
+	case ttl < time.Second:
+		ttl = time.Second
+	}
+	err = c.cache.Set(&cache.Item{
 		Ctx:   cacheCtx,
-		Key:   key,
+		Key:   c.prefix + key,
 		Value: jsonPayload, // Store JSON bytes
-		TTL:   TTL,
+		TTL:   ttl,
 	})
 	if err != nil {
 		return err
@@ -84,3 +177,64 @@ func SetCache[T any](key string, payload T, TTL time.Duration) error {
 
 	return nil
 }
+
+func (c *tieredCache) Delete(key string) error {
+	return c.cache.Delete(cacheCtx, c.prefix+key)
+}
+
+func (c *tieredCache) GetMany(keys []string) (map[string]interface{}, error) {
+	return getEach(c, keys)
+}
+
+func (c *tieredCache) SetMany(entries map[string]CacheItem) error {
+	return setEach(c, entries)
+}
+
+// Clear removes the keys under CACHE_PREFIX. It refuses to run without a
+// prefix, as that would remove the keys of every application on the server.
+func (c *tieredCache) Clear() error {
+	return c.clearPrefix("")
+}
+
+func (c *tieredCache) clearPrefix(prefix string) error {
+	if c.prefix+prefix == "" {
+		return ErrNoPrefix
+	}
+	return scanDelete(c.client, c.prefix+prefix, func(keys []string) error {
+		for _, key := range keys {
+			err := c.cache.Delete(cacheCtx, key)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// getEach implements GetMany with one Get per key.
+func getEach(c Cache, keys []string) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		var value interface{}
+		err := c.Get(key, &value)
+		if errors.Is(err, ErrCacheMiss) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// setEach implements SetMany with one Set per entry.
+func setEach(c Cache, entries map[string]CacheItem) error {
+	for key, item := range entries {
+		err := c.Set(key, item.Value, item.TTL)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
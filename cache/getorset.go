@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/Miskamyasa/utils/alerts"
+	"github.com/Miskamyasa/utils/async"
+)
+
+type flightKey struct {
+	cache Cache
+	key   string
+}
+
+type flightResult struct {
+	value interface{}
+	err   error
+}
+
+var (
+	flightsMu sync.Mutex
+	flights   = map[flightKey]*async.Task[flightResult]{}
+)
+
+// GetOrSet returns the value cached under key or, on a miss, calls loader,
+// caches its result for ttl and returns it. Concurrent callers missing the
+// same key share a single loader call. Loader errors are returned to all of
+// them and are not cached.
+func GetOrSet[T any](c Cache, key string, ttl time.Duration, loader func() (T, error)) (T, error) {
+	return getOrSet(c, key, loader,
+		func(dest *T) error { return c.Get(key, dest) },
+		func(value T) error { return c.Set(key, value, ttl) })
+}
+
+// GetOrSetWithOptions is like GetOrSet for entries stored with
+// SetWithOptions. A stale entry is returned like a fresh one; use GetStale
+// first to tell them apart.
+func GetOrSetWithOptions[T any](c Cache, key string, opts CacheOptions, loader func() (T, error)) (T, error) {
+	return getOrSet(c, key, loader,
+		func(dest *T) error {
+			_, err := GetStale(c, key, dest)
+			return err
+		},
+		func(value T) error { return SetWithOptions(c, key, value, opts) })
+}
+
+func getOrSet[T any](c Cache, key string, loader func() (T, error), get func(*T) error, set func(T) error) (T, error) {
+	var value T
+	err := get(&value)
+	if err == nil {
+		return value, nil
+	}
+
+	f := startFlight(c, key, func() flightResult {
+		value, err := loader()
+		if err != nil {
+			return flightResult{err: err}
+		}
+		err = set(value)
+		if err != nil {
+			alerts.Send("Failed to store the value in cache", err)
+		}
+		return flightResult{value: value}
+	})
+
+	res, err := f.AwaitResult()
+	if err == nil {
+		err = res.err
+	}
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	value, ok := res.value.(T)
+	if !ok && res.value != nil {
+		return value, fmt.Errorf("cache: value for %q has type %T, not %T", key, res.value, value)
+	}
+	return value, nil
+}
+
+// startFlight runs load for key unless a run for the same cache and key is
+// already in progress, in which case its future is returned.
+func startFlight(c Cache, key string, load func() flightResult) *async.Task[flightResult] {
+	// Caches that are not comparable cannot be used as map keys; they
+	// simply do not share loads. The value is checked rather than the type,
+	// since a wrapper such as WithNamespace holds a Cache of any type.
+	if !reflect.ValueOf(c).Comparable() {
+		return async.ExecAsync(load)
+	}
+
+	fk := flightKey{cache: c, key: key}
+	flightsMu.Lock()
+	defer flightsMu.Unlock()
+	if f, ok := flights[fk]; ok {
+		return f
+	}
+	f := async.ExecAsync(func() flightResult {
+		defer func() {
+			flightsMu.Lock()
+			delete(flights, fk)
+			flightsMu.Unlock()
+		}()
+		return load()
+	})
+	flights[fk] = f
+	return f
+}
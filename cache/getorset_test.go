@@ -0,0 +1,29 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+// unhashableCache is a valid Cache whose dynamic type cannot be a map key.
+type unhashableCache struct {
+	Cache
+	tags []string
+}
+
+func TestGetOrSetUnhashableCache(t *testing.T) {
+	c := WithNamespace(unhashableCache{Cache: NewMemoryCache(0)}, "u")
+	value, err := GetOrSet(c, "answer", time.Minute, func() (int, error) {
+		return 42, nil
+	})
+	if err != nil || value != 42 {
+		t.Fatalf("GetOrSet = %d, %v, want 42, nil", value, err)
+	}
+	value, err = GetOrSet(c, "answer", time.Minute, func() (int, error) {
+		t.Error("loader called for a cached key")
+		return 0, nil
+	})
+	if err != nil || value != 42 {
+		t.Fatalf("second GetOrSet = %d, %v, want 42, nil", value, err)
+	}
+}
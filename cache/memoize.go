@@ -0,0 +1,19 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+)
+
+// Memoize returns a function that caches the results of fn per key for ttl
+// in a private MemoryCache. Concurrent calls with the same key share one call
+// of fn through GetOrSet. Errors are returned but never cached. Results are
+// stored as JSON, so V must survive encoding/json.
+func Memoize[K comparable, V any](ttl time.Duration, fn func(K) (V, error)) func(K) (V, error) {
+	c := NewMemoryCache(0)
+	return func(key K) (V, error) {
+		return GetOrSet(c, fmt.Sprintf("%#v", key), ttl, func() (V, error) {
+			return fn(key)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"container/list"
+	"encoding/json"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sweepInterval is how often Set removes all expired entries of a
+// MemoryCache. Expired entries are also dropped whenever they are read.
+const sweepInterval = time.Minute
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// CacheStats are the counters reported by MemoryCache.Stats. Evictions
+// counts entries dropped because the cache was full or they had expired.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+}
+
+// MemoryCache is an in-process Cache, useful in development and tests.
+type MemoryCache struct {
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	// lru orders entries from most to least recently used.
+	lru       *list.List
+	lastSweep time.Time
+}
+
+// NewMemoryCache returns a MemoryCache holding at most maxEntries entries,
+// evicting the least recently used one when full. A maxEntries of zero or
+// less means no limit.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		lastSweep:  time.Now(),
+	}
+}
+
+func (c *MemoryCache) Get(key string, dest interface{}) error {
+	c.mu.Lock()
+	elem, ok := c.entries[key]
+	var value []byte
+	if ok {
+		entry := elem.Value.(*memoryEntry)
+		if entry.expired(time.Now()) {
+			c.remove(elem)
+			c.evictions.Add(1)
+			ok = false
+		} else {
+			c.lru.MoveToFront(elem)
+			value = entry.value
+		}
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		c.misses.Add(1)
+		return ErrCacheMiss
+	}
+	c.hits.Add(1)
+	return json.Unmarshal(value, dest)
+}
+
+func (c *MemoryCache) Set(key string, value interface{}, ttl time.Duration) error {
+	jsonPayload, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	entry := &memoryEntry{key: key, value: jsonPayload}
+	if ttl > 0 {
+		entry.expiresAt = now.Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now.Sub(c.lastSweep) >= sweepInterval {
+		c.sweep(now)
+	}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return nil
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	if c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+		c.evictions.Add(1)
+	}
+	return nil
+}
+
+func (c *MemoryCache) GetMany(keys []string) (map[string]interface{}, error) {
+	return getEach(c, keys)
+}
+
+func (c *MemoryCache) SetMany(entries map[string]CacheItem) error {
+	return setEach(c, entries)
+}
+
+func (c *MemoryCache) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	return nil
+}
+
+func (c *MemoryCache) Stats() CacheStats {
+	c.mu.Lock()
+	entries := len(c.entries)
+	c.mu.Unlock()
+	return CacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   entries,
+	}
+}
+
+func (c *MemoryCache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	return nil
+}
+
+func (c *MemoryCache) clearPrefix(prefix string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, elem := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(elem)
+		}
+	}
+	return nil
+}
+
+func (c *MemoryCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*memoryEntry).key)
+}
+
+func (c *MemoryCache) sweep(now time.Time) {
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if elem.Value.(*memoryEntry).expired(now) {
+			c.remove(elem)
+			c.evictions.Add(1)
+		}
+		elem = next
+	}
+	c.lastSweep = now
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type namespacedCache struct {
+	cache  Cache
+	prefix string
+}
+
+// WithNamespace returns a view of c in which every key is prefixed with
+// namespace + ":", so subsystems sharing a backend cannot collide.
+func WithNamespace(c Cache, namespace string) Cache {
+	return namespacedCache{cache: c, prefix: namespace + ":"}
+}
+
+func (c namespacedCache) Get(key string, dest interface{}) error {
+	return c.cache.Get(c.prefix+key, dest)
+}
+
+func (c namespacedCache) Set(key string, value interface{}, ttl time.Duration) error {
+	return c.cache.Set(c.prefix+key, value, ttl)
+}
+
+func (c namespacedCache) Delete(key string) error {
+	return c.cache.Delete(c.prefix + key)
+}
+
+func (c namespacedCache) GetMany(keys []string) (map[string]interface{}, error) {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+	found, err := c.cache.GetMany(prefixed)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{}, len(found))
+	for key, value := range found {
+		values[strings.TrimPrefix(key, c.prefix)] = value
+	}
+	return values, nil
+}
+
+func (c namespacedCache) SetMany(entries map[string]CacheItem) error {
+	prefixed := make(map[string]CacheItem, len(entries))
+	for key, item := range entries {
+		prefixed[c.prefix+key] = item
+	}
+	return c.cache.SetMany(prefixed)
+}
+
+// Clear removes the keys of this namespace only.
+func (c namespacedCache) Clear() error {
+	return c.clearPrefix("")
+}
+
+func (c namespacedCache) clearPrefix(prefix string) error {
+	pc, ok := c.cache.(prefixClearer)
+	if !ok {
+		return errors.New("cache: backend cannot clear a namespace")
+	}
+	return pc.clearPrefix(c.prefix + prefix)
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CacheOptions sets the two lifetimes of an entry stored by SetWithOptions.
+type CacheOptions struct {
+	// SoftTTL is how long the entry is fresh. After it the entry is still
+	// returned but reported as stale, so callers may refresh it. Zero means
+	// the entry stays fresh until HardTTL.
+	SoftTTL time.Duration
+	// HardTTL is when the entry is evicted. Zero means it is never
+	// evicted; a positive value smaller than SoftTTL is raised to SoftTTL.
+	HardTTL time.Duration
+}
+
+// softEntry wraps a value stored by SetWithOptions with its staleness time.
+type softEntry struct {
+	Value   interface{} `json:"value"`
+	StaleAt time.Time   `json:"stale_at"`
+}
+
+type rawSoftEntry struct {
+	Value   json.RawMessage `json:"value"`
+	StaleAt time.Time       `json:"stale_at"`
+}
+
+// SetWithOptions stores value under key in c with a soft and a hard TTL.
+// Read such entries with GetStale; this works with every backend.
+func SetWithOptions(c Cache, key string, value interface{}, opts CacheOptions) error {
+	entry := softEntry{Value: value}
+	if opts.SoftTTL > 0 {
+		entry.StaleAt = time.Now().Add(opts.SoftTTL)
+	}
+	hard := opts.HardTTL
+	if hard > 0 && hard < opts.SoftTTL {
+		hard = opts.SoftTTL
+	}
+	return c.Set(key, entry, hard)
+}
+
+// GetStale decodes the entry stored under key by SetWithOptions into dest
+// and reports whether it is past its SoftTTL. Missing keys give ErrCacheMiss.
+func GetStale(c Cache, key string, dest interface{}) (bool, error) {
+	var entry rawSoftEntry
+	err := c.Get(key, &entry)
+	if err != nil {
+		return false, err
+	}
+	err = json.Unmarshal(entry.Value, dest)
+	if err != nil {
+		return false, err
+	}
+	return !entry.StaleAt.IsZero() && !time.Now().Before(entry.StaleAt), nil
+}
+
+// SetCacheWithOptions is SetWithOptions on the default cache. Like SetCache
+// it does nothing in development.
+func SetCacheWithOptions[T any](key string, payload T, opts CacheOptions) error {
+	if !Enabled() {
+		return nil
+	}
+	return SetWithOptions(instance, key, payload, opts)
+}
+
+// GetCacheStale is GetStale on the default cache. Like GetCache it does
+// nothing in development.
+func GetCacheStale[T any](key string, payload *T) (bool, error) {
+	if !Enabled() {
+		return false, nil
+	}
+	return GetStale(instance, key, payload)
+}
@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrNoPrefix is returned when clearing a Redis cache without a key prefix,
+// which would remove the keys of every application sharing the server.
+var ErrNoPrefix = errors.New("cache: refusing to clear keys without a prefix")
+
+// RedisCache is a Cache stored in Redis. All keys are prefixed, so several
+// applications can share one Redis instance.
+type RedisCache struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisCache returns a RedisCache storing its keys as prefix+key.
+// Connection errors are returned to the caller rather than treated as misses.
+func NewRedisCache(client redis.UniversalClient, prefix string) *RedisCache {
+	return &RedisCache{client: client, prefix: prefix}
+}
+
+func (c *RedisCache) Get(key string, dest interface{}) error {
+	jsonPayload, err := c.client.Get(cacheCtx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return ErrCacheMiss
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(jsonPayload, dest)
+}
+
+func (c *RedisCache) Set(key string, value interface{}, ttl time.Duration) error {
+	jsonPayload, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if ttl < 0 {
+		ttl = 0
+	}
+	return c.client.Set(cacheCtx, c.prefix+key, jsonPayload, ttl).Err()
+}
+
+func (c *RedisCache) Delete(key string) error {
+	return c.client.Del(cacheCtx, c.prefix+key).Err()
+}
+
+// GetMany fetches all keys with a single MGET.
+func (c *RedisCache) GetMany(keys []string) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+	results, err := c.client.MGet(cacheCtx, prefixed...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, result := range results {
+		s, ok := result.(string)
+		if !ok {
+			continue
+		}
+		var value interface{}
+		err = json.Unmarshal([]byte(s), &value)
+		if err != nil {
+			return nil, err
+		}
+		values[keys[i]] = value
+	}
+	return values, nil
+}
+
+// SetMany stores all entries in one pipelined round trip.
+func (c *RedisCache) SetMany(entries map[string]CacheItem) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	pipe := c.client.Pipeline()
+	for key, item := range entries {
+		jsonPayload, err := json.Marshal(item.Value)
+		if err != nil {
+			return err
+		}
+		ttl := item.TTL
+		if ttl < 0 {
+			ttl = 0
+		}
+		pipe.Set(cacheCtx, c.prefix+key, jsonPayload, ttl)
+	}
+	_, err := pipe.Exec(cacheCtx)
+	return err
+}
+
+// Clear removes every key with the cache's prefix. It uses SCAN rather than
+// KEYS or FLUSHDB, so it neither blocks the server nor touches other keys.
+func (c *RedisCache) Clear() error {
+	return c.clearPrefix("")
+}
+
+func (c *RedisCache) clearPrefix(prefix string) error {
+	if c.prefix+prefix == "" {
+		return ErrNoPrefix
+	}
+	return scanDelete(c.client, c.prefix+prefix, func(keys []string) error {
+		return c.client.Del(cacheCtx, keys...).Err()
+	})
+}
+
+// scanBatchSize is the COUNT hint passed to SCAN.
+const scanBatchSize = 500
+
+// scanDelete passes every key starting with prefix to del, in batches.
+func scanDelete(client redis.UniversalClient, prefix string, del func(keys []string) error) error {
+	pattern := globEscaper.Replace(prefix) + "*"
+	var cursor uint64
+	for {
+		keys, next, err := client.Scan(cacheCtx, cursor, pattern, scanBatchSize).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			err = del(keys)
+			if err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"errors"
+	"time"
+)
+
+// TypedCache wraps a Cache for values of a single type T.
+type TypedCache[T any] struct {
+	cache Cache
+}
+
+func NewTypedCache[T any](c Cache) *TypedCache[T] {
+	return &TypedCache[T]{cache: c}
+}
+
+// Get returns the value under key. A miss is reported by false and a nil
+// error; other errors come from the backend or from decoding.
+func (c *TypedCache[T]) Get(key string) (T, bool, error) {
+	var value T
+	err := c.cache.Get(key, &value)
+	if errors.Is(err, ErrCacheMiss) {
+		return value, false, nil
+	}
+	if err != nil {
+		return value, false, err
+	}
+	return value, true, nil
+}
+
+func (c *TypedCache[T]) Set(key string, value T, ttl time.Duration) error {
+	return c.cache.Set(key, value, ttl)
+}
+
+func (c *TypedCache[T]) Delete(key string) error {
+	return c.cache.Delete(key)
+}
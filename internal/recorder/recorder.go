@@ -0,0 +1,78 @@
+package recorder
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+)
+
+// StatusRecorder records the status code and the number of bytes written by
+// a handler while passing the response through. Flush, Hijack and
+// http.ResponseController calls reach the underlying writer.
+type StatusRecorder struct {
+	http.ResponseWriter
+	// Status is the status sent, 200 if the handler wrote the body first.
+	Status int
+	// Bytes counts the body bytes written.
+	Bytes int
+	// Body, when set, receives a copy of the body.
+	Body io.Writer
+
+	wroteHeader bool
+}
+
+func New(w http.ResponseWriter) *StatusRecorder {
+	return &StatusRecorder{ResponseWriter: w, Status: http.StatusOK}
+}
+
+// Written reports whether the response has been started.
+func (r *StatusRecorder) Written() bool {
+	return r.wroteHeader
+}
+
+func (r *StatusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.Status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *StatusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	if r.Body != nil {
+		r.Body.Write(b)
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.Bytes += n
+	return n, err
+}
+
+func (r *StatusRecorder) Flush() {
+	r.wroteHeader = true
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hands over the connection, e.g. for WebSockets. The response then
+// counts as written with status 101.
+func (r *StatusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("recorder: the response writer does not support hijacking")
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && !r.wroteHeader {
+		r.Status = http.StatusSwitchingProtocols
+		r.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *StatusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
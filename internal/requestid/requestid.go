@@ -0,0 +1,17 @@
+// Package requestid carries the request ID in a context. It is shared by the
+// middlewares that set the ID and the alerts that report it.
+package requestid
+
+import "context"
+
+type key struct{}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, key{}, id)
+}
+
+// FromContext returns the request ID stored in ctx, or "" if there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(key{}).(string)
+	return id
+}
@@ -0,0 +1,89 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Miskamyasa/utils/alerts"
+	"github.com/Miskamyasa/utils/response"
+)
+
+// DefaultAuthHeader is the request header AuthMiddleware reads the token from.
+const DefaultAuthHeader = "auth-token"
+
+// AuthConfig configures NewAuthMiddleware.
+type AuthConfig struct {
+	// Header is the request header carrying the token. Empty means
+	// DefaultAuthHeader.
+	Header string
+	// Bearer reads the token from "Authorization: Bearer <token>" instead
+	// of Header.
+	Bearer bool
+	// Token is the expected token.
+	Token string
+	// Tokens are additional accepted tokens. During a rotation both the old
+	// and the new token can be listed.
+	Tokens []string
+	// Validate, when set, is used instead of comparing against Token.
+	Validate func(token string) bool
+}
+
+// AuthMiddleware checks the auth-token header against the AUTH_TOKEN
+// environment variable, read when the middleware is created.
+func AuthMiddleware(next http.Handler) http.Handler {
+	return NewAuthMiddleware(AuthConfig{Token: os.Getenv("AUTH_TOKEN")})(next)
+}
+
+func NewAuthMiddleware(cfg AuthConfig) func(http.Handler) http.Handler {
+	if cfg.Header == "" {
+		cfg.Header = DefaultAuthHeader
+	}
+	validate := cfg.Validate
+	if validate == nil {
+		var tokens [][]byte
+		for _, t := range append([]string{cfg.Token}, cfg.Tokens...) {
+			if t != "" {
+				tokens = append(tokens, []byte(t))
+			}
+		}
+		validate = func(token string) bool {
+			// Every token is compared so the timing does not reveal which
+			// one matched.
+			match := 0
+			for _, t := range tokens {
+				match |= subtle.ConstantTimeCompare([]byte(token), t)
+			}
+			return match == 1
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var token string
+			if cfg.Bearer {
+				token, _ = bearerToken(r)
+			} else {
+				token = r.Header.Get(cfg.Header)
+			}
+			if token == "" || !validate(token) {
+				alerts.Send("Unauthorized request. Invalid auth token or token is nil", nil)
+				response.SendUnauthorized(w, "Invalid auth token")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>"
+// header. It reports false for a missing header, another scheme or an empty
+// token.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,46 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Miskamyasa/utils/alerts"
+)
+
+func TestNewAuthMiddleware(t *testing.T) {
+	rec := &alerts.RecordingSink{}
+	defer alerts.SetSink(alerts.SetSink(rec))
+
+	handler := NewAuthMiddleware(AuthConfig{Token: "secret-token"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name   string
+		token  string
+		status int
+		alerts int
+	}{
+		{name: "correct token", token: "secret-token", status: http.StatusNoContent},
+		{name: "wrong token of the same length", token: "secret-tokem", status: http.StatusUnauthorized, alerts: 1},
+		{name: "missing token", status: http.StatusUnauthorized, alerts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec.Reset()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.token != "" {
+				r.Header.Set(DefaultAuthHeader, tt.token)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := len(rec.Alerts()); got != tt.alerts {
+				t.Errorf("sent %d alerts, want %d", got, tt.alerts)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package middlewares
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/Miskamyasa/utils/alerts"
+	"github.com/Miskamyasa/utils/response"
+)
+
+// BasicAuthRealm is the realm announced in the WWW-Authenticate header.
+const BasicAuthRealm = "Restricted"
+
+// BasicAuthMiddleware accepts requests carrying "Authorization: Basic"
+// credentials for one of users, a map of user names to passwords. Others get
+// a 401 with a WWW-Authenticate challenge and an alert is sent.
+func BasicAuthMiddleware(users map[string]string) func(http.Handler) http.Handler {
+	// Credentials are compared as hashes so the comparison takes the same
+	// time whatever their lengths.
+	hashed := make(map[[sha256.Size]byte][sha256.Size]byte, len(users))
+	for user, password := range users {
+		hashed[sha256.Sum256([]byte(user))] = sha256.Sum256([]byte(password))
+	}
+	valid := func(user, password string) bool {
+		want, known := hashed[sha256.Sum256([]byte(user))]
+		got := sha256.Sum256([]byte(password))
+		// Unknown users still go through a comparison.
+		match := subtle.ConstantTimeCompare(got[:], want[:])
+		return known && match == 1
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, password, ok := r.BasicAuth()
+			if !ok || !valid(user, password) {
+				alerts.Send("Unauthorized request. Invalid basic auth credentials", nil)
+				w.Header().Set("WWW-Authenticate", `Basic realm="`+BasicAuthRealm+`", charset="UTF-8"`)
+				response.SendUnauthorized(w, "Invalid credentials")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
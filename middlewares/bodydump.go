@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/Miskamyasa/utils/internal/recorder"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultBodyDumpLimit is how many bytes of each body BodyDumpMiddleware logs.
+const DefaultBodyDumpLimit = 4 << 10
+
+// BodyDumpMiddleware logs the request and response bodies of every request,
+// up to DefaultBodyDumpLimit bytes each, for debugging. See
+// NewBodyDumpMiddleware.
+func BodyDumpMiddleware(logger zerolog.Logger) func(http.Handler) http.Handler {
+	return NewBodyDumpMiddleware(logger, DefaultBodyDumpLimit)
+}
+
+// NewBodyDumpMiddleware logs up to limit bytes of the request and response
+// bodies of every request. The request body is captured as the handler reads
+// it and the response is passed through as it is written, so streaming
+// handlers keep working. Bodies may contain secrets, so the middleware does
+// nothing when ENV is "production".
+func NewBodyDumpMiddleware(logger zerolog.Logger, limit int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if os.Getenv("ENV") == "production" {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqBody := &cappedBuffer{limit: limit}
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.TeeReader(r.Body, reqBody), r.Body}
+
+			respBody := &cappedBuffer{limit: limit}
+			rec := recorder.New(w)
+			rec.Body = respBody
+			next.ServeHTTP(rec, r)
+
+			logger.Info().
+				Str("method", r.Method).
+				Str("path", r.URL.Path).
+				Int("status", rec.Status).
+				Str("request_body", reqBody.buf.String()).
+				Bool("request_body_truncated", reqBody.truncated).
+				Str("response_body", respBody.buf.String()).
+				Bool("response_body_truncated", respBody.truncated).
+				Msg("body dump")
+		})
+	}
+}
+
+// cappedBuffer keeps the first limit bytes written to it and drops the rest.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); len(p) > room {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
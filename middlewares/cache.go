@@ -0,0 +1,432 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Miskamyasa/utils/alerts"
+	"github.com/Miskamyasa/utils/async"
+	"github.com/Miskamyasa/utils/cache"
+	"github.com/Miskamyasa/utils/internal/recorder"
+)
+
+// DefaultCacheTTL is used by CacheMiddleware and by NewCacheMiddleware when
+// it is given a ttl of zero.
+const DefaultCacheTTL = time.Minute
+
+// DefaultNotFoundCacheTTL is how long 404 responses are cached when
+// CacheConfig.CacheNotFound is set without a NotFoundTTL.
+const DefaultNotFoundCacheTTL = 10 * time.Second
+
+// CacheConfig configures NewCacheMiddlewareWithConfig.
+type CacheConfig struct {
+	// TTL is how long an entry stays valid. Zero means DefaultCacheTTL.
+	TTL time.Duration
+	// TrustProxy keys clients by X-Forwarded-For / X-Real-IP instead of the
+	// connection address. Only enable it behind a trusted proxy.
+	TrustProxy bool
+	// SharedCache keys entries by path and query only, so all clients share
+	// the same cached responses.
+	SharedCache bool
+	// VaryHeaders lists request headers, such as Accept-Language, whose
+	// values select different variants of a response.
+	VaryHeaders []string
+	// KeyFunc builds the cache key of a request. When set, TrustProxy,
+	// SharedCache and VaryHeaders are ignored.
+	KeyFunc func(*http.Request) string
+	// CacheNotFound also stores 404 responses, for NotFoundTTL, so repeated
+	// lookups of missing resources do not reach the handler.
+	CacheNotFound bool
+	// NotFoundTTL is how long a 404 stays cached. Zero means
+	// DefaultNotFoundCacheTTL.
+	NotFoundTTL time.Duration
+	// StaleWhileRevalidate keeps entries this long past their TTL. A stale
+	// entry is still served, and triggers a background refresh of the entry.
+	StaleWhileRevalidate time.Duration
+}
+
+// GenerateCacheKey builds a key from the client IP, the path and the query
+// parameters sorted by name, so "a=1&b=2" and "b=2&a=1" share an entry.
+func GenerateCacheKey(req *http.Request) string {
+	return buildCacheKey(req, clientIP(req, false), nil)
+}
+
+// GenerateCacheKeyVary is like GenerateCacheKey but also folds the values of
+// varyHeaders into the key, so each variant of a response is cached apart.
+func GenerateCacheKeyVary(req *http.Request, varyHeaders []string) string {
+	return buildCacheKey(req, clientIP(req, false), varyHeaders)
+}
+
+func buildCacheKey(req *http.Request, client string, varyHeaders []string) string {
+	key := "cache:" + client + ":" + req.URL.Path
+	if query := req.URL.Query().Encode(); query != "" {
+		key += "?" + query
+	}
+	if len(varyHeaders) > 0 {
+		names := make([]string, len(varyHeaders))
+		for i, name := range varyHeaders {
+			names[i] = http.CanonicalHeaderKey(name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			key += "|" + name + "=" + url.QueryEscape(strings.Join(req.Header.Values(name), ","))
+		}
+	}
+	return key
+}
+
+func (cfg CacheConfig) key(req *http.Request) string {
+	if cfg.KeyFunc != nil {
+		return cfg.KeyFunc(req)
+	}
+	if cfg.SharedCache {
+		return buildCacheKey(req, "", cfg.VaryHeaders)
+	}
+	return buildCacheKey(req, clientIP(req, cfg.TrustProxy), cfg.VaryHeaders)
+}
+
+func CacheMiddleware(next http.Handler) http.Handler {
+	return NewCacheMiddleware(DefaultCacheTTL)(next)
+}
+
+// NewCacheMiddleware returns a CacheMiddleware whose entries expire after
+// ttl, so route groups can cache for different durations.
+func NewCacheMiddleware(ttl time.Duration) func(http.Handler) http.Handler {
+	return NewCacheMiddlewareWithConfig(CacheConfig{TTL: ttl})
+}
+
+// NewCacheMiddlewareWithConfig returns a cache middleware configured by cfg.
+// Concurrent requests missing the same entry are collapsed: one of them runs
+// the handler while the others wait and share its response. The shared
+// response is buffered, so the leader's client receives it only once the
+// handler has returned. A response that cannot be cached, such as an error,
+// is not shared; waiting requests then run the handler themselves.
+func NewCacheMiddlewareWithConfig(cfg CacheConfig) func(http.Handler) http.Handler {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultCacheTTL
+	}
+	if cfg.NotFoundTTL <= 0 {
+		cfg.NotFoundTTL = DefaultNotFoundCacheTTL
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			// Only safe methods are cached, and only GET responses are stored
+			// since a HEAD response has no body.
+			if req.Method != http.MethodGet && req.Method != http.MethodHead {
+				next.ServeHTTP(w, req)
+				return
+			}
+			key := cfg.key(req)
+
+			// A client asking for a fresh response skips the lookup, but the
+			// fresh response still refreshes the stored entry.
+			if !cache.Enabled() || hasCacheDirective(req.Header, "no-cache") {
+				cfg.serveAndStore(w, req, next, key)
+				return
+			}
+
+			var cached cachedResponse
+			stale, err := cache.GetCacheStale(key, &cached)
+			if err == nil {
+				cfg.serveCached(w, req, next, key, cached, stale)
+				return
+			}
+			if !errors.Is(err, cache.ErrCacheMiss) {
+				// The backend is failing; serve without it rather than
+				// piling loads onto it.
+				alertCacheUnavailable(err)
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			leader := false
+			cached, err = cache.GetOrSetWithOptions(cache.Default(), key, cfg.options(cfg.TTL), func() (cachedResponse, error) {
+				leader = true
+				resp := render(w.Header(), req, next)
+				ttl, ok := cfg.storeTTL(req, resp.Status, resp.Header)
+				if !ok {
+					return resp, uncacheableError{resp}
+				}
+				if ttl != cfg.TTL {
+					// GetOrSetWithOptions stores with cfg.TTL; other TTLs are
+					// stored here.
+					cfg.store(key, resp, ttl)
+					return resp, storedError{resp}
+				}
+				return resp, nil
+			})
+			if err == nil {
+				cfg.serveCached(w, req, next, key, cached, false)
+				return
+			}
+			var stored storedError
+			if errors.As(err, &stored) {
+				stored.resp.replay(w)
+				return
+			}
+			if !leader {
+				// The shared load failed; do not hand its outcome to this client.
+				next.ServeHTTP(w, req)
+				return
+			}
+			var uncacheable uncacheableError
+			if errors.As(err, &uncacheable) {
+				uncacheable.resp.replay(w)
+				return
+			}
+			// The handler panicked on the loader goroutine; raise it again
+			// here so recovery middlewares see it.
+			panic(err)
+		})
+	}
+}
+
+// serveCached replays a cached response and, when it is stale, refreshes it
+// in the background.
+func (cfg CacheConfig) serveCached(w http.ResponseWriter, req *http.Request, next http.Handler, key string, cached cachedResponse, stale bool) {
+	header := w.Header().Clone()
+	cached.replay(w)
+	if stale && cfg.StaleWhileRevalidate > 0 {
+		cfg.revalidate(header, req, next, key)
+	}
+}
+
+// cacheAlertInterval is the minimum time between two alerts about a failing
+// cache backend, so an outage does not send one per request.
+const cacheAlertInterval = time.Minute
+
+var lastCacheAlert atomic.Int64
+
+func alertCacheUnavailable(err error) {
+	now := time.Now().UnixNano()
+	last := lastCacheAlert.Load()
+	if now-last < int64(cacheAlertInterval) || !lastCacheAlert.CompareAndSwap(last, now) {
+		return
+	}
+	alerts.Send("Cache backend unavailable, serving without cache", err)
+}
+
+// serveAndStore passes the response through to the client and stores it
+// under key when it can be cached.
+func (cfg CacheConfig) serveAndStore(w http.ResponseWriter, req *http.Request, next http.Handler, key string) {
+	var body bytes.Buffer
+	before := w.Header().Clone()
+	rec := recorder.New(w)
+	rec.Body = &body
+	next.ServeHTTP(rec, req)
+	if ttl, ok := cfg.storeTTL(req, rec.Status, rec.Header()); ok {
+		cfg.store(key, cachedResponse{Status: rec.Status, Header: headersSetSince(before, rec.Header()), Body: body.Bytes()}, ttl)
+	}
+}
+
+// options keeps an entry fresh for ttl and StaleWhileRevalidate longer.
+func (cfg CacheConfig) options(ttl time.Duration) cache.CacheOptions {
+	return cache.CacheOptions{SoftTTL: ttl, HardTTL: ttl + cfg.StaleWhileRevalidate}
+}
+
+func (cfg CacheConfig) store(key string, resp cachedResponse, ttl time.Duration) {
+	err := cache.SetCacheWithOptions(key, resp, cfg.options(ttl))
+	if err != nil {
+		alerts.Send("Failed to store the response in cache", err)
+	}
+}
+
+// storeTTL reports whether a response may be stored and for how long. Only
+// GET responses that neither forbid storing nor set cookies are stored: 2xx
+// responses for TTL and, with CacheNotFound, 404s for NotFoundTTL.
+func (cfg CacheConfig) storeTTL(req *http.Request, status int, header http.Header) (time.Duration, bool) {
+	if req.Method != http.MethodGet {
+		return 0, false
+	}
+	if hasCacheDirective(header, "no-store") || header.Get("Set-Cookie") != "" {
+		return 0, false
+	}
+	switch {
+	case status >= 200 && status <= 299:
+		return cfg.TTL, true
+	case status == http.StatusNotFound && cfg.CacheNotFound:
+		return cfg.NotFoundTTL, true
+	default:
+		return 0, false
+	}
+}
+
+// uncacheableError carries a response that was computed for a shared load
+// but must not be cached or shared.
+type uncacheableError struct {
+	resp cachedResponse
+}
+
+func (uncacheableError) Error() string {
+	return "middlewares: response is not cacheable"
+}
+
+// storedError carries a response the loader already stored with its own
+// TTL; it may be shared like a cached one.
+type storedError struct {
+	resp cachedResponse
+}
+
+func (storedError) Error() string {
+	return "middlewares: response stored with its own TTL"
+}
+
+// revalidating holds the keys whose stale entries are being refreshed.
+var revalidating sync.Map
+
+// revalidate refreshes the stale entry under key in the background, unless a
+// refresh of it is already running. The handler gets a copy of req that is
+// not cancelled when the client goes away.
+func (cfg CacheConfig) revalidate(header http.Header, req *http.Request, next http.Handler, key string) {
+	if _, running := revalidating.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+	req = req.Clone(context.WithoutCancel(req.Context()))
+	f := async.ExecAsync(func() bool {
+		defer revalidating.Delete(key)
+		resp := render(header, req, next)
+		if ttl, ok := cfg.storeTTL(req, resp.Status, resp.Header); ok {
+			cfg.store(key, resp, ttl)
+		}
+		return true
+	})
+	go func() {
+		if _, err := f.AwaitResult(); err != nil {
+			alerts.Send("Failed to revalidate a cached response", err)
+		}
+	}()
+}
+
+// render runs next with a buffered response that starts with header.
+func render(header http.Header, req *http.Request, next http.Handler) cachedResponse {
+	rec := newBufferRecorder(header)
+	next.ServeHTTP(rec, req)
+	return rec.cachedResponse()
+}
+
+// cachedResponse is what CacheMiddleware stores for a request.
+type cachedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// replay writes the cached response. The stored headers are the ones the
+// handler set, so they replace those already on w, except Vary whose values
+// are merged. Headers the handler did not touch, e.g. the request ID of this
+// request, are kept.
+func (c *cachedResponse) replay(w http.ResponseWriter) {
+	for name, values := range c.Header {
+		if name == "Vary" {
+			w.Header()[name] = mergeVary(w.Header()[name], values)
+			continue
+		}
+		w.Header()[name] = slices.Clone(values)
+	}
+	w.WriteHeader(c.Status)
+	_, err := w.Write(c.Body)
+	if err != nil {
+		alerts.Send("Error writing the response", err)
+	}
+}
+
+// hasCacheDirective reports whether the Cache-Control header contains
+// directive. A request "Pragma: no-cache" counts as "no-cache".
+func hasCacheDirective(header http.Header, directive string) bool {
+	for _, value := range header.Values("Cache-Control") {
+		for _, d := range strings.Split(value, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(d), "=")
+			if strings.EqualFold(name, directive) {
+				return true
+			}
+		}
+	}
+	return directive == "no-cache" && strings.EqualFold(header.Get("Pragma"), "no-cache")
+}
+
+// mergeVary adds the header names listed in values to those in current,
+// leaving out names already listed.
+func mergeVary(current, values []string) []string {
+	merged := slices.Clone(current)
+	seen := map[string]bool{}
+	for _, value := range current {
+		for _, name := range strings.Split(value, ",") {
+			seen[http.CanonicalHeaderKey(strings.TrimSpace(name))] = true
+		}
+	}
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			key := http.CanonicalHeaderKey(name)
+			if name == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, name)
+		}
+	}
+	return merged
+}
+
+// headersSetSince returns the headers of after that are missing from or
+// differ in before: those set by the handler rather than by outer
+// middlewares. Only these are cached, so per-request headers such as
+// X-Request-ID never reach other clients.
+func headersSetSince(before, after http.Header) http.Header {
+	set := http.Header{}
+	for name, values := range after {
+		if !slices.Equal(before[name], values) {
+			set[name] = slices.Clone(values)
+		}
+	}
+	return set
+}
+
+// bufferRecorder keeps the whole response in memory instead of sending it.
+type bufferRecorder struct {
+	base        http.Header
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+// newBufferRecorder starts from a copy of header, so the handler sees the
+// headers already set by outer middlewares; they are left out of the
+// recorded response.
+func newBufferRecorder(header http.Header) *bufferRecorder {
+	return &bufferRecorder{base: header.Clone(), header: header.Clone(), status: http.StatusOK}
+}
+
+func (r *bufferRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *bufferRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+}
+
+func (r *bufferRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.body.Write(b)
+}
+
+func (r *bufferRecorder) cachedResponse() cachedResponse {
+	return cachedResponse{
+		Status: r.status,
+		Header: headersSetSince(r.base, r.header),
+		Body:   r.body.Bytes(),
+	}
+}
@@ -0,0 +1,118 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/Miskamyasa/utils/cache"
+)
+
+// useMemoryCache makes the cache middleware use a fresh in-memory cache for
+// the duration of the test.
+func useMemoryCache(t *testing.T) *cache.MemoryCache {
+	t.Helper()
+	t.Setenv("ENV", "test")
+	c := cache.NewMemoryCache(0)
+	prev := cache.Default()
+	cache.SetDefault(c)
+	t.Cleanup(func() { cache.SetDefault(prev) })
+	return c
+}
+
+func TestCacheMiddlewareSkipsPost(t *testing.T) {
+	c := useMemoryCache(t)
+	calls := 0
+	handler := NewCacheMiddleware(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(r.Method))
+	}))
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/items", nil))
+		if w.Code != http.StatusOK || w.Body.String() != http.MethodPost {
+			t.Fatalf("POST %d: got %d %q", i, w.Code, w.Body.String())
+		}
+	}
+	if calls != 2 {
+		t.Errorf("handler ran %d times for two POSTs, want 2", calls)
+	}
+	if entries := c.Stats().Entries; entries != 0 {
+		t.Errorf("POST stored %d cache entries, want 0", entries)
+	}
+
+	// A GET to the same path must not be served a POST response.
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+	if w.Body.String() != http.MethodGet || calls != 3 {
+		t.Errorf("GET after POST: got %q after %d handler calls", w.Body.String(), calls)
+	}
+}
+
+func TestCacheMiddlewareNotFound(t *testing.T) {
+	tests := []struct {
+		name          string
+		cacheNotFound bool
+		calls         int
+	}{
+		{name: "disabled", cacheNotFound: false, calls: 2},
+		{name: "enabled", cacheNotFound: true, calls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useMemoryCache(t)
+			calls := 0
+			handler := NewCacheMiddlewareWithConfig(CacheConfig{CacheNotFound: tt.cacheNotFound})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				http.NotFound(w, r)
+			}))
+
+			for i := 0; i < 2; i++ {
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+				if w.Code != http.StatusNotFound {
+					t.Fatalf("request %d: status = %d, want 404", i, w.Code)
+				}
+			}
+			if calls != tt.calls {
+				t.Errorf("handler ran %d times, want %d", calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestCacheMiddlewareReplaysHandlerHeaders(t *testing.T) {
+	useMemoryCache(t)
+	requests := 0
+	outer := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("X-Request-ID", strconv.Itoa(requests))
+			w.Header().Set("Vary", "Origin")
+			w.Header().Set("X-Frame-Options", "DENY")
+			next.ServeHTTP(w, r)
+		})
+	}
+	handler := outer(NewCacheMiddlewareWithConfig(CacheConfig{SharedCache: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		w.Header().Set("X-Frame-Options", "SAMEORIGIN")
+		w.Write([]byte("ok"))
+	})))
+
+	for i := 1; i <= 2; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/page", nil))
+		if got := w.Header()["Vary"]; !slices.Equal(got, []string{"Origin", "Accept"}) {
+			t.Errorf("request %d: Vary = %q, want [Origin Accept]", i, got)
+		}
+		if got := w.Header().Get("X-Frame-Options"); got != "SAMEORIGIN" {
+			t.Errorf("request %d: X-Frame-Options = %q, want SAMEORIGIN", i, got)
+		}
+		if got := w.Header().Get("X-Request-ID"); got != strconv.Itoa(i) {
+			t.Errorf("request %d: X-Request-ID = %q, want %d", i, got, i)
+		}
+	}
+}
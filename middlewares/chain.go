@@ -0,0 +1,15 @@
+package middlewares
+
+import "net/http"
+
+// Chain composes middlewares so that the first one listed is the outermost:
+// Chain(RecoveryMiddleware, AuthMiddleware)(h) equals
+// RecoveryMiddleware(AuthMiddleware(h)).
+func Chain(middlewares ...func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
@@ -0,0 +1,154 @@
+package middlewares
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Miskamyasa/utils/alerts"
+	"github.com/Miskamyasa/utils/internal/recorder"
+	"github.com/Miskamyasa/utils/response"
+)
+
+const (
+	DefaultCircuitBreakerThreshold = 5
+	DefaultCircuitBreakerCooldown  = 30 * time.Second
+)
+
+// CircuitBreakerConfig configures CircuitBreakerMiddleware.
+type CircuitBreakerConfig struct {
+	// Threshold is the number of consecutive 5xx responses that opens the
+	// circuit. Zero means DefaultCircuitBreakerThreshold.
+	Threshold int
+	// Cooldown is how long an open circuit fails fast before it lets a
+	// probe request through. Zero means DefaultCircuitBreakerCooldown.
+	Cooldown time.Duration
+	// KeyFunc selects the circuit of a request. By default each route
+	// pattern has its own circuit. The pattern is only known when the
+	// middleware wraps the handler of a route; around a whole ServeMux the
+	// key falls back to the URL path, one circuit per distinct path, so set
+	// KeyFunc there.
+	KeyFunc func(*http.Request) string
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+type circuit struct {
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+type circuitBreaker struct {
+	mu        sync.Mutex
+	circuits  map[string]*circuit
+	threshold int
+	cooldown  time.Duration
+}
+
+// allow reports whether a request may go through, and if not, how long the
+// circuit stays open. After the cooldown a single probe is let through.
+func (b *circuitBreaker) allow(key string, now time.Time) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[key]
+	if !ok {
+		return true, 0
+	}
+	switch c.state {
+	case circuitOpen:
+		if wait := c.openedAt.Add(b.cooldown).Sub(now); wait > 0 {
+			return false, wait
+		}
+		c.state = circuitHalfOpen
+		return true, 0
+	case circuitHalfOpen:
+		// The probe is still running.
+		return false, b.cooldown
+	default:
+		return true, 0
+	}
+}
+
+// record updates the circuit of key with the outcome of a request and
+// reports whether the circuit has just opened.
+func (b *circuitBreaker) record(key string, failed bool, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failed {
+		// Healthy circuits are not kept, so the map only holds failing ones.
+		delete(b.circuits, key)
+		return false
+	}
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{}
+		b.circuits[key] = c
+	}
+	c.failures++
+	if c.state == circuitHalfOpen || c.failures >= b.threshold {
+		opened := c.state != circuitOpen
+		c.state = circuitOpen
+		c.openedAt = now
+		return opened
+	}
+	return false
+}
+
+// CircuitBreakerMiddleware stops sending requests to a failing route. After
+// Threshold consecutive 5xx responses, or panics, the route's circuit opens
+// and requests get 503 Service Unavailable with Retry-After for Cooldown.
+// Then one probe request is let through: if it succeeds the circuit closes,
+// otherwise it opens again.
+func CircuitBreakerMiddleware(cfg CircuitBreakerConfig) func(http.Handler) http.Handler {
+	b := &circuitBreaker{
+		circuits:  map[string]*circuit{},
+		threshold: cfg.Threshold,
+		cooldown:  cfg.Cooldown,
+	}
+	if b.threshold <= 0 {
+		b.threshold = DefaultCircuitBreakerThreshold
+	}
+	if b.cooldown <= 0 {
+		b.cooldown = DefaultCircuitBreakerCooldown
+	}
+	key := cfg.KeyFunc
+	if key == nil {
+		key = func(r *http.Request) string {
+			if r.Pattern != "" {
+				return r.Pattern
+			}
+			return r.URL.Path
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			k := key(r)
+			ok, wait := b.allow(k, time.Now())
+			if !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				response.SendErrorResponse(w, http.StatusServiceUnavailable, "circuit_open", "Service temporarily unavailable")
+				return
+			}
+
+			rec := recorder.New(w)
+			failed := true
+			defer func() {
+				if b.record(k, failed, time.Now()) {
+					alerts.SendWithLevel(alerts.LevelWarning, "Circuit breaker opened for "+k, nil)
+				}
+			}()
+			next.ServeHTTP(rec, r)
+			failed = rec.Status >= 500
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package middlewares
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// TrustedProxyHops is the number of trusted proxies in front of the service,
+// used when a middleware is configured with TrustProxy. Each proxy appends
+// the address it received the request from to X-Forwarded-For, so the client
+// IP is the entry TrustedProxyHops places from the right. Entries further
+// left were sent by the client and cannot be trusted.
+var TrustedProxyHops = 1
+
+// clientIP returns the address of the client without the source port. When
+// trustProxy is set, the trusted X-Forwarded-For entry (see TrustedProxyHops)
+// is used, or X-Real-IP when the request has no X-Forwarded-For; only enable
+// it behind proxies that set these headers. A request with fewer
+// X-Forwarded-For entries than trusted hops did not pass through all the
+// proxies, so its connection address is used.
+func clientIP(req *http.Request, trustProxy bool) string {
+	if trustProxy {
+		var forwarded []string
+		for _, value := range req.Header.Values("X-Forwarded-For") {
+			for _, entry := range strings.Split(value, ",") {
+				forwarded = append(forwarded, strings.TrimSpace(entry))
+			}
+		}
+		hops := max(TrustedProxyHops, 1)
+		if len(forwarded) >= hops {
+			if ip := forwarded[len(forwarded)-hops]; ip != "" {
+				return ip
+			}
+		} else if len(forwarded) == 0 {
+			if ip := strings.TrimSpace(req.Header.Get("X-Real-IP")); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+type clientIPKey struct{}
+
+// ClientIPFromContext returns the client IP stored by ClientIPMiddleware, or
+// "" if there is none.
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
+
+// ClientIPMiddleware stores the client IP in the request context, where
+// handlers read it with ClientIPFromContext. With trustProxy the IP is taken
+// from X-Forwarded-For / X-Real-IP; only enable it behind a trusted proxy.
+func ClientIPMiddleware(trustProxy bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), clientIPKey{}, clientIP(r, trustProxy))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
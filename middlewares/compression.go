@@ -0,0 +1,177 @@
+package middlewares
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+
+	"github.com/Miskamyasa/utils/alerts"
+	"github.com/Miskamyasa/utils/response"
+)
+
+// DefaultCompressionMinSize is the smallest body CompressionMiddleware
+// compresses.
+const DefaultCompressionMinSize = 1024
+
+// CompressionConfig configures NewCompressionMiddleware.
+type CompressionConfig struct {
+	// MinSize is the smallest body, in bytes, worth compressing. Zero means
+	// DefaultCompressionMinSize.
+	MinSize int
+}
+
+// CompressionMiddleware gzips responses for clients that accept it.
+func CompressionMiddleware(next http.Handler) http.Handler {
+	return NewCompressionMiddleware(CompressionConfig{})(next)
+}
+
+// NewCompressionMiddleware gzips responses for clients that accept it. Bodies
+// smaller than cfg.MinSize and already compressed content types such as
+// images and video are sent as is.
+func NewCompressionMiddleware(cfg CompressionConfig) func(http.Handler) http.Handler {
+	if cfg.MinSize <= 0 {
+		cfg.MinSize = DefaultCompressionMinSize
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if !response.AcceptsGzip(r) || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			gw := &gzipWriter{ResponseWriter: w, minSize: cfg.MinSize, status: http.StatusOK}
+			next.ServeHTTP(gw, r)
+			// Not deferred: after a panic the buffered partial body must be
+			// dropped so recovery middlewares can still send their error.
+			gw.close()
+		})
+	}
+}
+
+// gzipWriter holds back the start of the body until it knows whether the
+// response is worth compressing.
+type gzipWriter struct {
+	http.ResponseWriter
+	minSize     int
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         bytes.Buffer
+	gz          *gzip.Writer
+}
+
+func (g *gzipWriter) WriteHeader(status int) {
+	if g.wroteHeader {
+		return
+	}
+	g.status = status
+	g.wroteHeader = true
+	// Informational and bodiless responses are passed through at once.
+	if status < 200 || status == http.StatusNoContent || status == http.StatusNotModified {
+		g.decide(false)
+	}
+}
+
+func (g *gzipWriter) Write(b []byte) (int, error) {
+	g.wroteHeader = true
+	if !g.decided {
+		g.buf.Write(b)
+		if g.buf.Len() < g.minSize {
+			return len(b), nil
+		}
+		g.decide(true)
+		return len(b), g.flushBuffer()
+	}
+	if g.gz != nil {
+		return g.gz.Write(b)
+	}
+	return g.ResponseWriter.Write(b)
+}
+
+func (g *gzipWriter) Flush() {
+	if !g.decided {
+		g.decide(g.buf.Len() > 0)
+		err := g.flushBuffer()
+		if err != nil {
+			return
+		}
+	}
+	if g.gz != nil {
+		_ = g.gz.Flush()
+	}
+	if f, ok := g.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (g *gzipWriter) Unwrap() http.ResponseWriter {
+	return g.ResponseWriter
+}
+
+// decide writes the real header, compressing only if wanted and worthwhile
+// for the content type.
+func (g *gzipWriter) decide(compress bool) {
+	g.decided = true
+	header := g.Header()
+	if header.Get("Content-Type") == "" && g.buf.Len() > 0 {
+		header.Set("Content-Type", http.DetectContentType(g.buf.Bytes()))
+	}
+	if compress && header.Get("Content-Encoding") == "" && compressible(header.Get("Content-Type")) {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		g.gz = gzip.NewWriter(g.ResponseWriter)
+	}
+	g.ResponseWriter.WriteHeader(g.status)
+}
+
+func (g *gzipWriter) flushBuffer() error {
+	if g.buf.Len() == 0 {
+		return nil
+	}
+	var err error
+	if g.gz != nil {
+		_, err = g.gz.Write(g.buf.Bytes())
+	} else {
+		_, err = g.ResponseWriter.Write(g.buf.Bytes())
+	}
+	g.buf.Reset()
+	return err
+}
+
+func (g *gzipWriter) close() {
+	if !g.decided {
+		if !g.wroteHeader {
+			return
+		}
+		g.decide(false)
+	}
+	err := g.flushBuffer()
+	if err == nil && g.gz != nil {
+		err = g.gz.Close()
+	}
+	if err != nil {
+		alerts.Send("Error writing the compressed response", err)
+	}
+}
+
+// compressible reports whether a content type benefits from gzip.
+func compressible(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	switch {
+	case strings.HasPrefix(mediaType, "image/") && mediaType != "image/svg+xml",
+		strings.HasPrefix(mediaType, "video/"),
+		strings.HasPrefix(mediaType, "audio/"),
+		strings.HasPrefix(mediaType, "font/woff"):
+		return false
+	}
+	switch mediaType {
+	case "application/zip", "application/gzip", "application/x-gzip",
+		"application/x-7z-compressed", "application/x-rar-compressed",
+		"application/pdf", "application/octet-stream":
+		return false
+	}
+	return true
+}
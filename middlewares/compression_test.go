@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Miskamyasa/utils/alerts"
+)
+
+func TestCompressionMiddlewarePanicKeepsRecoveryResponse(t *testing.T) {
+	defer alerts.SetSink(alerts.SetSink(alerts.NopSink{}))
+	handler := RecoveryMiddleware(CompressionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"partial":`))
+		panic("boom")
+	})))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", w.Code)
+	}
+	if body := w.Body.String(); body == `{"partial":` {
+		t.Errorf("the partial body was sent: %q", body)
+	}
+}
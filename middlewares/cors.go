@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Miskamyasa/utils/response"
+)
+
+// CORSConfig configures CORSMiddleware.
+type CORSConfig struct {
+	// AllowedOrigins lists the origins allowed to call the API. "*" allows
+	// any origin.
+	AllowedOrigins []string
+	// AllowedMethods and AllowedHeaders override response.DefaultCORSMethods
+	// and response.DefaultCORSHeaders when set.
+	AllowedMethods []string
+	AllowedHeaders []string
+	// MaxAge tells browsers how long to cache a preflight response.
+	MaxAge time.Duration
+}
+
+// CORSMiddleware adds CORS headers to responses and answers preflight
+// requests with 204 without calling the next handler.
+func CORSMiddleware(cfg CORSConfig) func(http.Handler) http.Handler {
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			response.SetCORSHeaders(w, cfg.AllowedOrigins, origin)
+			if w.Header().Get("Access-Control-Allow-Origin") != "" {
+				if methods != "" {
+					w.Header().Set("Access-Control-Allow-Methods", methods)
+				}
+				if headers != "" {
+					w.Header().Set("Access-Control-Allow-Headers", headers)
+				}
+			}
+
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if !preflight {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if cfg.MaxAge > 0 && w.Header().Get("Access-Control-Allow-Origin") != "" {
+				w.Header().Set("Access-Control-Max-Age", maxAge)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
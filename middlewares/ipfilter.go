@@ -0,0 +1,85 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/Miskamyasa/utils/response"
+)
+
+// IPFilterConfig configures IPFilterMiddleware. Entries are CIDR ranges such
+// as "10.0.0.0/8" or single addresses such as "192.0.2.1".
+type IPFilterConfig struct {
+	// Allow, when not empty, admits only clients inside one of the ranges.
+	Allow []string
+	// Deny rejects clients inside one of the ranges, even if they are
+	// allowed.
+	Deny []string
+	// TrustProxy takes the client IP from X-Forwarded-For / X-Real-IP. Only
+	// enable it behind a trusted proxy.
+	TrustProxy bool
+}
+
+// IPFilterMiddleware rejects clients outside cfg.Allow or inside cfg.Deny
+// with 403 Forbidden. Clients whose address cannot be parsed are rejected as
+// well. The ranges are parsed once; a malformed one is returned as an error.
+func IPFilterMiddleware(cfg IPFilterConfig) (func(http.Handler) http.Handler, error) {
+	allow, err := parsePrefixes(cfg.Allow)
+	if err != nil {
+		return nil, err
+	}
+	deny, err := parsePrefixes(cfg.Deny)
+	if err != nil {
+		return nil, err
+	}
+	allowed := func(addr netip.Addr) bool {
+		if containsAddr(deny, addr) {
+			return false
+		}
+		return len(allow) == 0 || containsAddr(allow, addr)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			addr, err := netip.ParseAddr(clientIP(r, cfg.TrustProxy))
+			if err != nil || !allowed(addr.Unmap()) {
+				response.SendErrorResponse(w, http.StatusForbidden, "forbidden", "Access denied")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+func parsePrefixes(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, fmt.Errorf("middlewares: invalid IP %q: %w", entry, err)
+			}
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("middlewares: invalid CIDR %q: %w", entry, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
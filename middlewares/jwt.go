@@ -0,0 +1,88 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/Miskamyasa/utils/response"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTConfig configures JWTMiddleware. Exactly one of Secret and PublicKey
+// should be set.
+type JWTConfig struct {
+	// Secret verifies HMAC (HS256/384/512) signed tokens.
+	Secret []byte
+	// PublicKey verifies RSA, ECDSA or Ed25519 signed tokens.
+	PublicKey interface{}
+}
+
+type claimsKey struct{}
+
+// ClaimsFromContext returns the claims stored by JWTMiddleware.
+func ClaimsFromContext(ctx context.Context) (jwt.MapClaims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(jwt.MapClaims)
+	return claims, ok
+}
+
+// JWTMiddleware verifies the token in "Authorization: Bearer <token>",
+// including its exp and nbf claims, and stores the claims in the request
+// context. Invalid tokens get a 401 JSON error. It panics when cfg has
+// neither a Secret nor a PublicKey, or a PublicKey of an unsupported type,
+// since every token would otherwise be checked against an empty key.
+func JWTMiddleware(cfg JWTConfig) func(http.Handler) http.Handler {
+	var methods []string
+	var key interface{}
+	switch k := cfg.PublicKey.(type) {
+	case nil:
+		if len(cfg.Secret) == 0 {
+			panic("middlewares: JWTMiddleware needs a Secret or a PublicKey")
+		}
+		methods = []string{"HS256", "HS384", "HS512"}
+		key = cfg.Secret
+	case *rsa.PublicKey:
+		methods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+		key = k
+	case *ecdsa.PublicKey:
+		methods = []string{"ES256", "ES384", "ES512"}
+		key = k
+	case ed25519.PublicKey:
+		methods = []string{"EdDSA"}
+		key = k
+	default:
+		panic(fmt.Sprintf("middlewares: unsupported JWT public key type %T", cfg.PublicKey))
+	}
+	if reflect.ValueOf(key).IsZero() {
+		panic("middlewares: JWTMiddleware needs a non-empty key")
+	}
+	parser := jwt.NewParser(jwt.WithValidMethods(methods))
+	keyFunc := func(*jwt.Token) (interface{}, error) {
+		return key, nil
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenString, ok := bearerToken(r)
+			if !ok {
+				response.SendErrorResponse(w, http.StatusUnauthorized, "missing_token", "Missing bearer token")
+				return
+			}
+
+			claims := jwt.MapClaims{}
+			_, err := parser.ParseWithClaims(tokenString, claims, keyFunc)
+			if err != nil {
+				response.SendErrorResponse(w, http.StatusUnauthorized, "invalid_token", "Invalid or expired token")
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), claimsKey{}, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Miskamyasa/utils/alerts"
+	"github.com/Miskamyasa/utils/internal/recorder"
+
+	"github.com/rs/zerolog"
+)
+
+// RequestLog describes a served request.
+type RequestLog struct {
+	Method   string
+	Path     string
+	Status   int
+	Bytes    int
+	Duration time.Duration
+}
+
+// LoggingMiddleware logs every request with the shared service logger.
+func LoggingMiddleware(next http.Handler) http.Handler {
+	logger := alerts.CreateLogger().With().Str("component", "http").Logger()
+	return NewLoggingMiddleware(zerologRequestLogger(logger))(next)
+}
+
+// NewLoggingMiddleware calls logFn once for every request after it has been
+// served.
+func NewLoggingMiddleware(logFn func(RequestLog)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := recorder.New(w)
+			next.ServeHTTP(rec, r)
+			logFn(RequestLog{
+				Method:   r.Method,
+				Path:     r.URL.Path,
+				Status:   rec.Status,
+				Bytes:    rec.Bytes,
+				Duration: time.Since(start),
+			})
+		})
+	}
+}
+
+// JSONRequestLogger returns a logFn for NewLoggingMiddleware that writes one
+// JSON object per request to out.
+func JSONRequestLogger(out io.Writer) func(RequestLog) {
+	return zerologRequestLogger(zerolog.New(out).With().Timestamp().Logger())
+}
+
+func zerologRequestLogger(logger zerolog.Logger) func(RequestLog) {
+	return func(l RequestLog) {
+		logger.Info().
+			Str("method", l.Method).
+			Str("path", l.Path).
+			Int("status", l.Status).
+			Int("bytes", l.Bytes).
+			Dur("duration", l.Duration).
+			Msg("request")
+	}
+}
@@ -0,0 +1,54 @@
+package middlewares
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/Miskamyasa/utils/internal/recorder"
+	"github.com/Miskamyasa/utils/response"
+)
+
+// MaxBodyBytesMiddleware limits request bodies to limit bytes. Requests that
+// announce a larger Content-Length are rejected with 413 before the handler
+// runs. Otherwise the body is wrapped in http.MaxBytesReader, so reads past the
+// limit fail with *http.MaxBytesError; if the handler returns without writing
+// a response after that, the middleware answers 413 itself. Apply it per
+// route to use different limits.
+func MaxBodyBytesMiddleware(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				sendTooLarge(w)
+				return
+			}
+			body := &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, limit)}
+			r.Body = body
+
+			rec := recorder.New(w)
+			next.ServeHTTP(rec, r)
+			if body.exceeded && !rec.Written() {
+				sendTooLarge(w)
+			}
+		})
+	}
+}
+
+func sendTooLarge(w http.ResponseWriter) {
+	response.SendErrorResponse(w, http.StatusRequestEntityTooLarge, "request_too_large", "Request body too large")
+}
+
+// limitedBody remembers whether the handler ran into the body limit.
+type limitedBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		b.exceeded = true
+	}
+	return n, err
+}
@@ -0,0 +1,183 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Miskamyasa/utils/alerts"
+	"github.com/Miskamyasa/utils/internal/recorder"
+)
+
+// MetricsRecorder receives the measurements taken by MetricsMiddleware. It
+// can be implemented on top of any metrics library; Metrics is a dependency
+// free implementation.
+type MetricsRecorder interface {
+	RequestStarted()
+	RequestFinished(method, path string, status int, duration time.Duration)
+}
+
+// UnmatchedPathLabel is the default path label of requests that matched no
+// ServeMux pattern.
+const UnmatchedPathLabel = "unmatched"
+
+// MetricsConfig configures MetricsMiddleware.
+type MetricsConfig struct {
+	// Recorder receives the measurements.
+	Recorder MetricsRecorder
+	// PathLabel returns the path label of a request. By default it is the
+	// ServeMux pattern that matched, or UnmatchedPathLabel if there is none,
+	// so unknown paths cannot create new series. The pattern is read after
+	// the handler returns, so it is seen when the middleware wraps the
+	// ServeMux directly or the handler of a route, but not through
+	// middlewares that pass a copy of the request, e.g. with WithContext;
+	// set PathLabel in that case.
+	PathLabel func(r *http.Request) string
+}
+
+// MetricsMiddleware records the number of requests, the requests in flight
+// and their latency, by method, path and status code.
+func MetricsMiddleware(cfg MetricsConfig) func(http.Handler) http.Handler {
+	pathLabel := cfg.PathLabel
+	if pathLabel == nil {
+		pathLabel = func(r *http.Request) string {
+			if r.Pattern != "" {
+				return r.Pattern
+			}
+			return UnmatchedPathLabel
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			cfg.Recorder.RequestStarted()
+			rec := recorder.New(w)
+			defer func() {
+				cfg.Recorder.RequestFinished(r.Method, pathLabel(r), rec.Status, time.Since(start))
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency
+// histogram kept by Metrics.
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type requestLabels struct {
+	method string
+	path   string
+	status int
+}
+
+type requestSeries struct {
+	count   uint64
+	sum     float64
+	buckets []uint64
+}
+
+// Metrics is an in-memory MetricsRecorder that can be scraped in the
+// Prometheus text exposition format through Handler.
+type Metrics struct {
+	mu       sync.Mutex
+	inFlight int64
+	series   map[requestLabels]*requestSeries
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{series: make(map[requestLabels]*requestSeries)}
+}
+
+func (m *Metrics) RequestStarted() {
+	m.mu.Lock()
+	m.inFlight++
+	m.mu.Unlock()
+}
+
+func (m *Metrics) RequestFinished(method, path string, status int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight--
+
+	labels := requestLabels{method: method, path: path, status: status}
+	s, ok := m.series[labels]
+	if !ok {
+		s = &requestSeries{buckets: make([]uint64, len(DefaultLatencyBuckets))}
+		m.series[labels] = s
+	}
+	seconds := duration.Seconds()
+	s.count++
+	s.sum += seconds
+	for i, bound := range DefaultLatencyBuckets {
+		if seconds <= bound {
+			s.buckets[i]++
+		}
+	}
+}
+
+// Handler serves the collected metrics, e.g. on /metrics.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, err := w.Write([]byte(m.text()))
+		if err != nil {
+			alerts.Send("Error writing the response", err)
+		}
+	})
+}
+
+func (m *Metrics) text() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	labels := make([]requestLabels, 0, len(m.series))
+	for l := range m.series {
+		labels = append(labels, l)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		a, b := labels[i], labels[j]
+		if a.path != b.path {
+			return a.path < b.path
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		return a.status < b.status
+	})
+
+	var sb strings.Builder
+	sb.WriteString("# HELP http_requests_total Total number of HTTP requests.\n")
+	sb.WriteString("# TYPE http_requests_total counter\n")
+	for _, l := range labels {
+		fmt.Fprintf(&sb, "http_requests_total{%s} %d\n", l.format(), m.series[l].count)
+	}
+
+	sb.WriteString("# HELP http_requests_in_flight Number of HTTP requests being served.\n")
+	sb.WriteString("# TYPE http_requests_in_flight gauge\n")
+	fmt.Fprintf(&sb, "http_requests_in_flight %d\n", m.inFlight)
+
+	sb.WriteString("# HELP http_request_duration_seconds HTTP request latency.\n")
+	sb.WriteString("# TYPE http_request_duration_seconds histogram\n")
+	for _, l := range labels {
+		s := m.series[l]
+		for i, bound := range DefaultLatencyBuckets {
+			le := strconv.FormatFloat(bound, 'g', -1, 64)
+			fmt.Fprintf(&sb, "http_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", l.format(), le, s.buckets[i])
+		}
+		fmt.Fprintf(&sb, "http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", l.format(), s.count)
+		fmt.Fprintf(&sb, "http_request_duration_seconds_sum{%s} %g\n", l.format(), s.sum)
+		fmt.Fprintf(&sb, "http_request_duration_seconds_count{%s} %d\n", l.format(), s.count)
+	}
+	return sb.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func (l requestLabels) format() string {
+	return fmt.Sprintf(`method="%s",path="%s",status="%d"`,
+		labelEscaper.Replace(l.method), labelEscaper.Replace(l.path), l.status)
+}
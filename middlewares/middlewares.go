@@ -1,74 +1,99 @@
 package middlewares
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"log"
 	"net/http"
-	"os"
 	"runtime/debug"
 
 	"github.com/Miskamyasa/utils/alerts"
-	"github.com/Miskamyasa/utils/cache"
+	"github.com/Miskamyasa/utils/internal/recorder"
 	"github.com/Miskamyasa/utils/response"
 )
 
-func GenerateCacheKey(req *http.Request) string {
-	ip := req.RemoteAddr
-	path := req.URL.Path
-	return "cache:" + ip + ":" + path
-}
+// PanicHandler handles a value recovered from a panicking handler. If the
+// handler had already started the response, writes to w are discarded. The
+// stack of the panic is available through PanicStack(r.Context()).
+type PanicHandler func(w http.ResponseWriter, r *http.Request, recovered interface{})
 
-func CacheMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		var payload *interface{}
-		err := cache.GetCache(GenerateCacheKey(req), &payload)
-		if err == nil && payload != nil {
-			w.Header().Set("Content-Type", "application/json")
-			err := json.NewEncoder(w).Encode(payload)
-			if err != nil {
-				return
-			}
-			return
-		}
-		next.ServeHTTP(w, req)
-	})
+type panicStackKey struct{}
+
+// PanicStack returns the stack trace captured when the panic was recovered,
+// or nil outside a PanicHandler.
+func PanicStack(ctx context.Context) []byte {
+	stack, _ := ctx.Value(panicStackKey{}).([]byte)
+	return stack
 }
 
 func RecoveryMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		defer func() {
-			if err := recover(); err != nil {
-				// Convert interface{} to an error
-				var errMsg error
-				if e, ok := err.(error); ok {
-					errMsg = e
-				} else {
-					errMsg = fmt.Errorf("%v", err)
+	return NewRecoveryMiddleware(DefaultPanicHandler)(next)
+}
+
+// NewRecoveryMiddleware returns a RecoveryMiddleware that hands recovered
+// panics to handler.
+func NewRecoveryMiddleware(handler PanicHandler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := recorder.New(w)
+			defer func() {
+				if err := recover(); err != nil {
+					// The stdlib uses this panic to abort a response on purpose.
+					if err == http.ErrAbortHandler {
+						panic(err)
+					}
+					// Capture the stack here, while the panicking frames are still on it.
+					stack := debug.Stack()
+					var out http.ResponseWriter = rec
+					if rec.Written() {
+						out = discardWriter{w}
+					}
+					handler(out, r.WithContext(context.WithValue(r.Context(), panicStackKey{}, stack)), err)
 				}
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
 
-				// Log the error and stack trace
-				log.Printf("Recovered from panic: %v\nStack trace: %s", errMsg, debug.Stack())
+// DefaultPanicHandler logs the panic with its stack trace, sends an alert
+// carrying the stack and request metadata and responds with a 500 JSON error.
+func DefaultPanicHandler(w http.ResponseWriter, r *http.Request, recovered interface{}) {
+	// Convert interface{} to an error
+	var errMsg error
+	if e, ok := recovered.(error); ok {
+		errMsg = e
+	} else {
+		errMsg = fmt.Errorf("%v", recovered)
+	}
 
-				// Send alert and internal server error response
-				alerts.Send("Panic recovery", errMsg)
+	stack := PanicStack(r.Context())
+	if stack == nil {
+		stack = debug.Stack()
+	}
 
-				// Send internal server error response
-				response.SendInternalServerError(w)
-			}
-		}()
-		next.ServeHTTP(w, r)
+	// Log the error and stack trace
+	log.Printf("Recovered from panic: %v\nStack trace: %s", errMsg, stack)
+
+	// Send alert and internal server error response
+	alerts.SendContext(r.Context(), "Panic recovery", errMsg, map[string]any{
+		"method": r.Method,
+		"url":    r.URL.String(),
+		"stack":  string(stack),
 	})
+
+	// Send internal server error response
+	response.SendErrorResponse(w, http.StatusInternalServerError, "internal_error", "Internal Server Error")
 }
 
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		token := r.Header.Get("auth-token")
-		if token != os.Getenv("AUTH_TOKEN") {
-			alerts.Send("Unauthorized request. Invalid auth token or token is nil", nil)
-			response.SendInternalServerError(w)
-			return
-		}
-		next.ServeHTTP(w, r)
-	})
+// discardWriter drops everything written to it; it stands in for a response
+// that has already been started.
+type discardWriter struct {
+	http.ResponseWriter
+}
+
+func (discardWriter) WriteHeader(int) {}
+
+func (discardWriter) Write(b []byte) (int, error) {
+	return len(b), nil
 }
@@ -0,0 +1,103 @@
+package middlewares
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Miskamyasa/utils/response"
+)
+
+// DefaultRateLimitIdleTimeout is how long a client bucket may stay unused
+// before it is removed, when RateLimitConfig.IdleTimeout is zero.
+const DefaultRateLimitIdleTimeout = 10 * time.Minute
+
+// RateLimitConfig configures RateLimitMiddleware.
+type RateLimitConfig struct {
+	// Rate is the number of requests per second a client may make.
+	Rate float64
+	// Burst is the number of requests a client may make at once. Values
+	// below 1 are treated as 1.
+	Burst int
+	// TrustProxy identifies clients by X-Forwarded-For / X-Real-IP.
+	TrustProxy bool
+	// IdleTimeout controls when unused buckets are removed.
+	IdleTimeout time.Duration
+}
+
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+type rateLimiter struct {
+	cfg       RateLimitConfig
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// allow takes a token from the client's bucket. If none is left it returns
+// how long the client has to wait for the next one.
+func (l *rateLimiter) allow(client string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= l.cfg.IdleTimeout {
+		for key, b := range l.buckets {
+			if now.Sub(b.lastSeen) >= l.cfg.IdleTimeout {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	burst := float64(l.cfg.Burst)
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: burst}
+		l.buckets[client] = b
+	} else {
+		b.tokens = math.Min(burst, b.tokens+now.Sub(b.lastSeen).Seconds()*l.cfg.Rate)
+	}
+	b.lastSeen = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	if l.cfg.Rate <= 0 {
+		return false, l.cfg.IdleTimeout
+	}
+	return false, time.Duration((1 - b.tokens) / l.cfg.Rate * float64(time.Second))
+}
+
+// RateLimitMiddleware limits every client, keyed by IP, with a token bucket.
+// Requests over the limit get 429 Too Many Requests with a Retry-After header.
+func RateLimitMiddleware(cfg RateLimitConfig) func(http.Handler) http.Handler {
+	if cfg.Burst < 1 {
+		cfg.Burst = 1
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = DefaultRateLimitIdleTimeout
+	}
+	limiter := &rateLimiter{
+		cfg:       cfg,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ok, wait := limiter.allow(clientIP(r, cfg.TrustProxy), time.Now())
+			if !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				response.SendErrorResponse(w, http.StatusTooManyRequests, "rate_limited", "Too many requests")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package middlewares
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/Miskamyasa/utils/response"
+)
+
+// RequireJSONMiddleware rejects POST, PUT and PATCH requests whose
+// Content-Type is not JSON with 415 Unsupported Media Type. Parameters such
+// as "; charset=utf-8" are allowed, and so are "+json" types. Other methods
+// pass through untouched.
+func RequireJSONMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+				response.SendErrorResponse(w, http.StatusUnsupportedMediaType, "unsupported_media_type", "Content-Type must be application/json")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
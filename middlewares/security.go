@@ -0,0 +1,49 @@
+package middlewares
+
+import "net/http"
+
+// SecurityHeaderDisabled turns off a header in SecurityHeadersConfig.
+const SecurityHeaderDisabled = "-"
+
+// SecurityHeadersConfig configures SecurityHeadersMiddleware. An empty field
+// uses the default value, SecurityHeaderDisabled leaves the header unset.
+type SecurityHeadersConfig struct {
+	// ContentTypeOptions defaults to "nosniff".
+	ContentTypeOptions string
+	// FrameOptions defaults to "DENY".
+	FrameOptions string
+	// ReferrerPolicy defaults to "strict-origin-when-cross-origin".
+	ReferrerPolicy string
+	// StrictTransportSecurity is not sent by default. Only enable it for
+	// sites served over HTTPS, e.g. "max-age=63072000; includeSubDomains".
+	StrictTransportSecurity string
+	// ContentSecurityPolicy is not sent by default.
+	ContentSecurityPolicy string
+}
+
+// SecurityHeadersMiddleware sets common hardening headers on every response.
+func SecurityHeadersMiddleware(cfg SecurityHeadersConfig) func(http.Handler) http.Handler {
+	headers := map[string]string{}
+	add := func(name, value, fallback string) {
+		if value == "" {
+			value = fallback
+		}
+		if value != "" && value != SecurityHeaderDisabled {
+			headers[name] = value
+		}
+	}
+	add("X-Content-Type-Options", cfg.ContentTypeOptions, "nosniff")
+	add("X-Frame-Options", cfg.FrameOptions, "DENY")
+	add("Referrer-Policy", cfg.ReferrerPolicy, "strict-origin-when-cross-origin")
+	add("Strict-Transport-Security", cfg.StrictTransportSecurity, "")
+	add("Content-Security-Policy", cfg.ContentSecurityPolicy, "")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for name, value := range headers {
+				w.Header().Set(name, value)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
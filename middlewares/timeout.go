@@ -0,0 +1,108 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Miskamyasa/utils/response"
+)
+
+// TimeoutMiddleware gives every request a deadline of d on r.Context(). If
+// the handler has not finished by then, the client gets 503 Service
+// Unavailable and whatever the handler writes afterwards is discarded.
+func TimeoutMiddleware(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return timeoutHandler(next, d, http.StatusServiceUnavailable)
+	}
+}
+
+// DeadlineMiddleware gives every request a deadline of d on r.Context(), like
+// TimeoutMiddleware, but answers 504 Gateway Timeout when the handler is too
+// slow. Work a handler starts in the background should honor the deadline:
+// start it with async.ExecAsyncContext(r.Context(), ...) or wait for it with
+// AwaitContext(r.Context()), so it stops with the request instead of
+// outliving it.
+func DeadlineMiddleware(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return timeoutHandler(next, d, http.StatusGatewayTimeout)
+	}
+}
+
+func timeoutHandler(next http.Handler, d time.Duration, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), d)
+		defer cancel()
+		r = r.WithContext(ctx)
+
+		tw := &timeoutWriter{header: make(http.Header), status: http.StatusOK}
+		done := make(chan struct{})
+		panicked := make(chan interface{}, 1)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- p
+				}
+			}()
+			next.ServeHTTP(tw, r)
+			close(done)
+		}()
+
+		select {
+		case p := <-panicked:
+			// Re-panic on the serving goroutine so RecoveryMiddleware
+			// can handle it.
+			panic(p)
+		case <-done:
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			for key, values := range tw.header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(tw.status)
+			_, _ = w.Write(tw.body.Bytes())
+		case <-ctx.Done():
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			tw.timedOut = true
+			response.SendErrorResponse(w, status, "timeout", "The request took too long")
+		}
+	})
+}
+
+// timeoutWriter buffers the handler's response until it finishes in time and
+// rejects writes once the deadline has passed.
+type timeoutWriter struct {
+	mu          sync.Mutex
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.status = status
+	tw.wroteHeader = true
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.wroteHeader = true
+	return tw.body.Write(b)
+}
@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+)
+
+// TrailingSlashMode selects how TrailingSlashMiddleware normalizes paths.
+type TrailingSlashMode int
+
+const (
+	// TrailingSlashRedirect answers with a 308 redirect to the path without
+	// the trailing slash.
+	TrailingSlashRedirect TrailingSlashMode = iota
+	// TrailingSlashRewrite strips the trailing slash from r.URL.Path before
+	// calling the next handler.
+	TrailingSlashRewrite
+)
+
+// TrailingSlashMiddleware makes "/items/" and "/items" reach the same route,
+// and share cache entries, by removing trailing slashes. The root "/" is left
+// alone. In TrailingSlashRedirect mode only GET and HEAD requests are
+// redirected, keeping their query string; other requests, whose clients may
+// not resend a body, and paths that would redirect off-site such as "//host/"
+// are rewritten instead.
+func TrailingSlashMiddleware(mode TrailingSlashMode) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path := strings.TrimRight(r.URL.Path, "/")
+			if path == r.URL.Path || path == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			redirect := mode == TrailingSlashRedirect &&
+				(r.Method == http.MethodGet || r.Method == http.MethodHead) &&
+				!strings.HasPrefix(path, "//")
+			if redirect {
+				u := *r.URL
+				u.Path = path
+				u.RawPath = strings.TrimRight(u.RawPath, "/")
+				w.Header().Set("Location", u.RequestURI())
+				w.WriteHeader(http.StatusPermanentRedirect)
+				return
+			}
+
+			r2 := new(http.Request)
+			*r2 = *r
+			u := *r.URL
+			u.Path = path
+			u.RawPath = strings.TrimRight(u.RawPath, "/")
+			r2.URL = &u
+			next.ServeHTTP(w, r2)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/Miskamyasa/utils/response"
+)
+
+// DefaultMaxBodyBytes limits the bodies read by DecodeJSONBody.
+var DefaultMaxBodyBytes int64 = 1 << 20
+
+// ErrUnsupportedMediaType is returned by DecodeJSONBody for requests that do
+// not declare a JSON Content-Type.
+var ErrUnsupportedMediaType = errors.New("request: Content-Type must be application/json")
+
+var errMultipleValues = errors.New("request: body must contain a single JSON value")
+
+// DecodeJSONBody decodes the JSON request body into dst. Unknown fields,
+// trailing data, a missing or non-JSON Content-Type and bodies larger than
+// DefaultMaxBodyBytes are rejected. On failure it writes a 400, 413 or 415
+// JSON error to w and returns the error, so the handler only has to return.
+func DecodeJSONBody(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	if !isJSON(r.Header.Get("Content-Type")) {
+		response.SendErrorResponse(w, http.StatusUnsupportedMediaType, "unsupported_media_type", "Content-Type must be application/json")
+		return ErrUnsupportedMediaType
+	}
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, DefaultMaxBodyBytes))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(dst)
+	if err == nil {
+		// Anything after the value, such as "{}{}" or "{}x", is rejected too.
+		if _, next := dec.Token(); next != io.EOF {
+			err = errMultipleValues
+		}
+	}
+	if err == nil {
+		return nil
+	}
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		response.SendErrorResponse(w, http.StatusRequestEntityTooLarge, "request_too_large", decodeErrorMessage(err))
+		return err
+	}
+	response.SendErrorResponse(w, http.StatusBadRequest, "invalid_body", decodeErrorMessage(err))
+	return err
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// decodeErrorMessage describes a decoding failure in terms a client can act on.
+func decodeErrorMessage(err error) string {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.Is(err, errMultipleValues):
+		return "Request body must contain a single JSON value"
+	case errors.Is(err, io.EOF):
+		return "Request body must not be empty"
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return "Request body contains badly-formed JSON"
+	case errors.As(err, &syntaxErr):
+		return fmt.Sprintf("Request body contains badly-formed JSON (at position %d)", syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		if typeErr.Field != "" {
+			return fmt.Sprintf("Request body contains an invalid value for the %q field (at position %d)", typeErr.Field, typeErr.Offset)
+		}
+		return fmt.Sprintf("Request body contains an invalid value (at position %d)", typeErr.Offset)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return "Request body contains unknown field " + strings.TrimPrefix(err.Error(), "json: unknown field ")
+	case errors.As(err, &tooLarge):
+		return fmt.Sprintf("Request body must not be larger than %d bytes", tooLarge.Limit)
+	default:
+		return "Request body could not be decoded"
+	}
+}
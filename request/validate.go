@@ -0,0 +1,215 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldError is a single violation found by Validate. Field is the path of
+// the field as it appears in JSON, e.g. "items[2].name".
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors lists every violation found by Validate. It encodes to
+// JSON as a list of FieldError, ready for a 422 response.
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Field + ": " + fe.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Fields maps each invalid field to its first violation.
+func (e ValidationErrors) Fields() map[string]string {
+	fields := make(map[string]string, len(e))
+	for _, fe := range e {
+		if _, ok := fields[fe.Field]; !ok {
+			fields[fe.Field] = fe.Message
+		}
+	}
+	return fields
+}
+
+// Validate checks v, a struct or a pointer to one, against its `validate`
+// struct tags and returns ValidationErrors listing every violation, or nil.
+// Rules are separated by commas:
+//
+//	required  the value must not be the zero value
+//	omitempty skip the remaining rules when the value is the zero value
+//	min=N     numbers must be at least N, strings, slices and maps must have
+//	          at least N elements
+//	max=N     like min, as an upper bound
+//	email     the string must be an email address
+//	oneof=a b the value must be one of the space separated words
+//
+// Nested structs, pointers to structs and slices of them are validated
+// recursively.
+func Validate(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return errors.New("request: Validate called with a nil pointer")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return errors.New("request: Validate needs a struct")
+	}
+	var errs ValidationErrors
+	validateStruct(rv, "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateStruct(v reflect.Value, prefix string, errs *ValidationErrors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := jsonName(field)
+		if name == "-" {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		fv := v.Field(i)
+		if tag := field.Tag.Get("validate"); tag != "" && tag != "-" {
+			validateRules(fv, path, tag, errs)
+		}
+		validateNested(fv, path, errs)
+	}
+}
+
+// validateNested descends into structs, pointers and slices.
+func validateNested(v reflect.Value, path string, errs *ValidationErrors) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			validateNested(v.Elem(), path, errs)
+		}
+	case reflect.Struct:
+		validateStruct(v, path, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateNested(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+func validateRules(v reflect.Value, path, tag string, errs *ValidationErrors) {
+	fail := func(rule, format string, args ...interface{}) {
+		*errs = append(*errs, FieldError{Field: path, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+	empty := v.IsZero()
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch {
+		case name == "required" && empty:
+			fail(name, "is required")
+			return
+		case name == "required":
+			continue
+		case name == "omitempty" && empty:
+			return
+		case name == "omitempty":
+			continue
+		}
+
+		value := v
+		for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				// Only required applies to a nil pointer.
+				return
+			}
+			value = value.Elem()
+		}
+		switch name {
+		case "min", "max":
+			limit, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				fail(name, "has an invalid %s rule %q", name, arg)
+				continue
+			}
+			n, unit, ok := measure(value)
+			if !ok {
+				continue
+			}
+			if name == "min" && n < limit || name == "max" && n > limit {
+				bound := "at least"
+				if name == "max" {
+					bound = "at most"
+				}
+				if unit != "" {
+					fail(name, "must have %s %s %s", bound, arg, unit)
+				} else {
+					fail(name, "must be %s %s", bound, arg)
+				}
+			}
+		case "email":
+			if value.Kind() != reflect.String {
+				continue
+			}
+			addr, err := mail.ParseAddress(value.String())
+			if err != nil || addr.Address != value.String() {
+				fail(name, "must be a valid email address")
+			}
+		case "oneof":
+			allowed := strings.Fields(arg)
+			got := fmt.Sprint(value.Interface())
+			found := false
+			for _, a := range allowed {
+				if a == got {
+					found = true
+					break
+				}
+			}
+			if !found {
+				fail(name, "must be one of %s", strings.Join(allowed, ", "))
+			}
+		}
+	}
+}
+
+// measure returns the number min and max compare against: the value of a
+// number, or the length of a string, slice or map together with its unit.
+func measure(v reflect.Value) (n float64, unit string, ok bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), "", true
+	case reflect.String:
+		return float64(len([]rune(v.String()))), "characters", true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), "elements", true
+	default:
+		return 0, "", false
+	}
+}
+
+// jsonName returns the name a field has in JSON.
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
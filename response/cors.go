@@ -0,0 +1,29 @@
+package response
+
+import "net/http"
+
+const (
+	DefaultCORSMethods = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	DefaultCORSHeaders = "Content-Type, Authorization, auth-token"
+)
+
+// SetCORSHeaders allows origin to access the response if it is listed in
+// allowedOrigins or the list contains "*". Nothing is set for other origins.
+func SetCORSHeaders(w http.ResponseWriter, allowedOrigins []string, origin string) {
+	w.Header().Add("Vary", "Origin")
+	if origin == "" || !originAllowed(allowedOrigins, origin) {
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Access-Control-Allow-Methods", DefaultCORSMethods)
+	w.Header().Set("Access-Control-Allow-Headers", DefaultCORSHeaders)
+}
+
+func originAllowed(allowedOrigins []string, origin string) bool {
+	for _, allowed := range allowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
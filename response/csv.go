@@ -0,0 +1,32 @@
+package response
+
+import (
+	"encoding/csv"
+	"net/http"
+
+	"github.com/Miskamyasa/utils/alerts"
+)
+
+// SendCSV sends headers followed by rows as a CSV attachment named filename.
+// Fields containing commas, quotes or newlines are quoted by encoding/csv.
+// The rows are streamed, so an error while writing them can only be
+// reported through alerts.
+func SendCSV(w http.ResponseWriter, filename string, headers []string, rows [][]string) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", attachment(cleanFilename(filename)))
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	if len(headers) > 0 {
+		cw.Write(headers)
+	}
+	for _, row := range rows {
+		if cw.Write(row) != nil {
+			break
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		alerts.Send("Error writing the CSV response", err)
+	}
+}
@@ -0,0 +1,50 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+
+	"github.com/Miskamyasa/utils/alerts"
+)
+
+// SendJsonResponseWithETag sends payload as JSON with an ETag computed from
+// the encoded body. If the request's If-None-Match matches, it responds with
+// 304 Not Modified and no body instead.
+func SendJsonResponseWithETag(w http.ResponseWriter, r *http.Request, payload interface{}) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		alerts.Send("Error encoding the response", err)
+		SendInternalServerError(w)
+		return
+	}
+
+	hash := fnv.New64a()
+	hash.Write(body)
+	etag := fmt.Sprintf(`"%x"`, hash.Sum64())
+
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(append(body, '\n'))
+	if err != nil {
+		alerts.Send("Error writing the response", err)
+	}
+}
+
+// etagMatches implements the weak comparison used for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package response
+
+import (
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/Miskamyasa/utils/alerts"
+)
+
+// SendFile sends data as a downloadable attachment named filename. The
+// content type is taken from the file extension, falling back to sniffing
+// the data. The body is omitted for HEAD requests.
+func SendFile(w http.ResponseWriter, r *http.Request, data []byte, filename string) {
+	filename = cleanFilename(filename)
+
+	contentType := mime.TypeByExtension(path.Ext(filename))
+	if contentType == "" && len(data) > 0 {
+		contentType = http.DetectContentType(data)
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", attachment(filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead || len(data) == 0 {
+		return
+	}
+
+	_, err := w.Write(data)
+	if err != nil {
+		alerts.Send("Error writing the response", err)
+	}
+}
+
+// cleanFilename strips directories and control characters from filename.
+func cleanFilename(filename string) string {
+	filename = path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	return strings.Map(func(c rune) rune {
+		if c < ' ' || c == 0x7f {
+			return -1
+		}
+		return c
+	}, filename)
+}
+
+// attachment returns a Content-Disposition value for downloading a file
+// named filename.
+func attachment(filename string) string {
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+	if disposition == "" {
+		return "attachment"
+	}
+	return disposition
+}
@@ -0,0 +1,79 @@
+package response
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Miskamyasa/utils/alerts"
+)
+
+// SendJsonResponseGzip is like SendJsonResponse but compresses the body with
+// gzip when the client accepts it.
+func SendJsonResponseGzip(w http.ResponseWriter, r *http.Request, payload interface{}) {
+	w.Header().Add("Vary", "Accept-Encoding")
+	if !AcceptsGzip(r) {
+		SendJsonResponse(w, payload)
+		return
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		alerts.Send("Error encoding the response", err)
+		SendInternalServerError(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Encoding", "gzip")
+	w.Header().Del("Content-Length")
+
+	gz := gzip.NewWriter(w)
+	defer func() {
+		err := gz.Close()
+		if err != nil {
+			alerts.Send("Error closing the gzip writer", err)
+		}
+	}()
+
+	_, err = gz.Write(append(body, '\n'))
+	if err != nil {
+		alerts.Send("Error writing the response", err)
+	}
+}
+
+// AcceptsGzip reports whether the Accept-Encoding header allows gzip. An
+// explicit gzip entry takes precedence over "*".
+func AcceptsGzip(r *http.Request) bool {
+	star := false
+	for _, part := range strings.Split(strings.Join(r.Header.Values("Accept-Encoding"), ","), ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.TrimSpace(coding)
+		switch {
+		case strings.EqualFold(coding, "gzip"):
+			return qValue(params) > 0
+		case coding == "*":
+			star = qValue(params) > 0
+		}
+	}
+	return star
+}
+
+// qValue returns the weight from header parameters such as "q=0.5",
+// defaulting to 1.
+func qValue(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || strings.TrimSpace(key) != "q" {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0
+		}
+		return q
+	}
+	return 1
+}
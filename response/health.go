@@ -0,0 +1,86 @@
+package response
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// DefaultHealthCheckTimeout bounds how long NewHealthCheckHandler waits for
+// all checks together.
+const DefaultHealthCheckTimeout = 5 * time.Second
+
+// HealthCheck is a named dependency check. Check returns nil when the
+// dependency is healthy.
+type HealthCheck struct {
+	Name  string
+	Check func() error
+}
+
+type HealthStatus struct {
+	Status       string            `json:"status"`
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+const (
+	statusHealthy   = "healthy"
+	statusUnhealthy = "unhealthy"
+	statusTimeout   = "timeout"
+)
+
+// NewHealthCheckHandler returns a handler that runs all checks concurrently
+// and responds with 200 when every one passes, or 503 otherwise. The body
+// lists the status of each dependency.
+func NewHealthCheckHandler(checks ...HealthCheck) http.HandlerFunc {
+	return NewHealthCheckHandlerWithTimeout(DefaultHealthCheckTimeout, checks...)
+}
+
+// NewHealthCheckHandlerWithTimeout is like NewHealthCheckHandler but reports
+// checks still running after timeout as failed.
+func NewHealthCheckHandlerWithTimeout(timeout time.Duration, checks ...HealthCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		type result struct {
+			name string
+			err  error
+		}
+		results := make(chan result, len(checks))
+		for _, check := range checks {
+			go func() {
+				results <- result{name: check.Name, err: check.Check()}
+			}()
+		}
+
+		health := HealthStatus{
+			Status:       statusHealthy,
+			Dependencies: make(map[string]string, len(checks)),
+		}
+		for _, check := range checks {
+			health.Dependencies[check.Name] = statusTimeout
+		}
+	wait:
+		for range checks {
+			select {
+			case res := <-results:
+				if res.err != nil {
+					health.Dependencies[res.name] = statusUnhealthy
+				} else {
+					health.Dependencies[res.name] = statusHealthy
+				}
+			case <-ctx.Done():
+				break wait
+			}
+		}
+
+		status := http.StatusOK
+		for _, s := range health.Dependencies {
+			if s != statusHealthy {
+				health.Status = statusUnhealthy
+				status = http.StatusServiceUnavailable
+			}
+		}
+		SendJsonResponseWithStatus(w, status, health)
+	}
+}
@@ -0,0 +1,41 @@
+package response
+
+import (
+	"net/http"
+	"time"
+)
+
+// SendWithLastModified sends payload as JSON with a Last-Modified header set
+// to modTime. If the request's If-Modified-Since is at or after modTime, it
+// responds with 304 Not Modified and no body instead. Times are compared to
+// the second, the precision of HTTP dates. As the HTTP spec requires,
+// If-Modified-Since is ignored when the request carries If-None-Match, and
+// for methods other than GET and HEAD.
+func SendWithLastModified(w http.ResponseWriter, r *http.Request, payload interface{}, modTime time.Time) {
+	if modTime.IsZero() {
+		SendJsonResponse(w, payload)
+		return
+	}
+	modTime = modTime.UTC().Truncate(time.Second)
+	w.Header().Set("Last-Modified", modTime.Format(http.TimeFormat))
+
+	if notModifiedSince(r, modTime) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	SendJsonResponse(w, payload)
+}
+
+func notModifiedSince(r *http.Request, modTime time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if r.Header.Get("If-None-Match") != "" {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !modTime.After(since)
+}
@@ -0,0 +1,82 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Miskamyasa/utils/alerts"
+)
+
+// SendNegotiated sends payload as JSON or plain text, depending on which one
+// the Accept header prefers. JSON is used for a missing header and for */*.
+// If neither can be produced it responds with 406 Not Acceptable.
+func SendNegotiated(w http.ResponseWriter, r *http.Request, payload interface{}) {
+	w.Header().Add("Vary", "Accept")
+	switch negotiate(r.Header.Get("Accept"), "application/json", "text/plain") {
+	case "application/json":
+		SendJsonResponse(w, payload)
+	case "text/plain":
+		sendText(w, payload)
+	default:
+		sendStatusText(w, http.StatusNotAcceptable, "Supported types are application/json and text/plain")
+	}
+}
+
+func sendText(w http.ResponseWriter, payload interface{}) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	var err error
+	switch v := payload.(type) {
+	case string:
+		_, err = w.Write([]byte(v))
+	case []byte:
+		_, err = w.Write(v)
+	default:
+		_, err = fmt.Fprintf(w, "%v", v)
+	}
+	if err != nil {
+		alerts.Send("Error writing the response", err)
+	}
+}
+
+// negotiate returns the offer with the highest weight in the Accept header,
+// preferring earlier offers on ties, or "" if none is acceptable.
+func negotiate(accept string, offers ...string) string {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		q := acceptWeight(accept, offer)
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// acceptWeight returns the weight of the most specific media range in accept
+// that matches offer.
+func acceptWeight(accept, offer string) float64 {
+	offerType, _, _ := strings.Cut(offer, "/")
+	q, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		mediaRange, params, _ := strings.Cut(part, ";")
+		mediaRange = strings.ToLower(strings.TrimSpace(mediaRange))
+
+		s := -1
+		switch {
+		case mediaRange == offer:
+			s = 2
+		case mediaRange == offerType+"/*":
+			s = 1
+		case mediaRange == "*/*":
+			s = 0
+		}
+		if s > specificity {
+			q, specificity = qValue(params), s
+		}
+	}
+	return q
+}
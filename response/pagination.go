@@ -0,0 +1,41 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Miskamyasa/utils/alerts"
+)
+
+type Paginated struct {
+	Data       interface{} `json:"data"`
+	Pagination Pagination  `json:"pagination"`
+}
+
+type Pagination struct {
+	Page       int `json:"page"`
+	PageSize   int `json:"pageSize"`
+	Total      int `json:"total"`
+	TotalPages int `json:"totalPages"`
+}
+
+// SendPaginated sends items wrapped in a Paginated envelope. A pageSize of 0
+// or less is reported as misuse and results in totalPages being 0.
+func SendPaginated(w http.ResponseWriter, items interface{}, page, pageSize, total int) {
+	totalPages := 0
+	if pageSize > 0 {
+		totalPages = (total + pageSize - 1) / pageSize
+	} else {
+		alerts.Send("Invalid page size", fmt.Errorf("page size must be positive, got %d", pageSize))
+	}
+
+	SendJsonResponseWithStatus(w, http.StatusOK, Paginated{
+		Data: items,
+		Pagination: Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: totalPages,
+		},
+	})
+}
@@ -0,0 +1,32 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Miskamyasa/utils/alerts"
+)
+
+// Problem is an RFC 7807 problem details object.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// SendProblem writes p as application/problem+json. An empty Type defaults to
+// "about:blank". A Status outside the 4xx/5xx range is reported and replaced
+// with 500.
+func SendProblem(w http.ResponseWriter, p Problem) {
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Status < 400 || p.Status > 599 {
+		alerts.Send("Invalid problem status", fmt.Errorf("status %d is not an error status", p.Status))
+		p.Status = http.StatusInternalServerError
+	}
+
+	writeJSON(w, p.Status, "application/problem+json", p)
+}
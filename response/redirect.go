@@ -0,0 +1,72 @@
+package response
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// AllowedRedirectHosts lists the external hosts SendRedirect may send
+// clients to, e.g. an OAuth provider. Hosts are compared case-insensitively
+// and include the port if one is used.
+var AllowedRedirectHosts []string
+
+// SendRedirect redirects the client to target. GET and HEAD requests get a
+// 301 or 302; other methods get a 308 or 307 so the method and body are
+// preserved. To prevent open redirects, target must be a path on this site
+// or an http(s) URL on r.Host or one of AllowedRedirectHosts; anything else
+// is answered with 400 Bad Request.
+func SendRedirect(w http.ResponseWriter, r *http.Request, target string, permanent bool) {
+	if !safeRedirect(r, target) {
+		SendBadRequest(w, "Invalid redirect target")
+		return
+	}
+
+	safeMethod := r.Method == http.MethodGet || r.Method == http.MethodHead
+	var status int
+	switch {
+	case permanent && safeMethod:
+		status = http.StatusMovedPermanently
+	case permanent:
+		status = http.StatusPermanentRedirect
+	case safeMethod:
+		status = http.StatusFound
+	default:
+		status = http.StatusTemporaryRedirect
+	}
+	w.Header().Set("Location", target)
+	w.WriteHeader(status)
+}
+
+func safeRedirect(r *http.Request, target string) bool {
+	// Browsers treat a backslash like a slash, so "/\evil.com" would leave
+	// the site; control characters can hide such tricks.
+	if target == "" || strings.Contains(target, `\`) || strings.ContainsFunc(target, func(c rune) bool {
+		return c < ' ' || c == 0x7f
+	}) {
+		return false
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" && u.Host == "" && u.Opaque == "" {
+		// A relative reference stays on this site.
+		return true
+	}
+	if u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	if u.Host == "" || u.User != nil {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, host := range AllowedRedirectHosts {
+		if strings.EqualFold(u.Host, host) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,19 +1,71 @@
 package response
 
 import (
+	"bytes"
 	"encoding/json"
 	"net/http"
 
 	"github.com/Miskamyasa/utils/alerts"
 )
 
+// Response is the standard envelope for JSON responses.
+type Response struct {
+	Message string      `json:"message"`
+	Payload interface{} `json:"payload"`
+}
+
+func NewResponse(message string, payload interface{}) Response {
+	return Response{
+		Message: message,
+		Payload: payload,
+	}
+}
+
+// ErrorResponse is the JSON body written by SendErrorResponse.
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
+
+type ErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 func SendJsonResponse(w http.ResponseWriter, payload interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	err := json.NewEncoder(w).Encode(payload)
+	writeJSON(w, http.StatusOK, "application/json", payload)
+}
+
+func SendJsonResponseWithStatus(w http.ResponseWriter, status int, payload interface{}) {
+	writeJSON(w, status, "application/json", payload)
+}
+
+// SendJsonResponseCT is like SendJsonResponse but sends contentType, such as
+// "application/vnd.myapp.v2+json", instead of "application/json". An empty
+// contentType keeps the default.
+func SendJsonResponseCT(w http.ResponseWriter, payload interface{}, contentType string) {
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	writeJSON(w, http.StatusOK, contentType, payload)
+}
+
+// writeJSON encodes payload into a buffer first, so that an encoding error
+// results in a clean 500 instead of a partial body with the wrong status.
+func writeJSON(w http.ResponseWriter, status int, contentType string, payload interface{}) {
+	var buf bytes.Buffer
+	err := json.NewEncoder(&buf).Encode(payload)
 	if err != nil {
 		alerts.Send("Error encoding the response", err)
+		SendInternalServerError(w)
 		return
 	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		alerts.Send("Error writing the response", err)
+	}
 }
 
 func SendInternalServerError(w http.ResponseWriter) {
@@ -25,13 +77,51 @@ func SendInternalServerError(w http.ResponseWriter) {
 }
 
 func SendBadRequest(w http.ResponseWriter, msg string) {
-	w.WriteHeader(http.StatusBadRequest)
-	_, err := w.Write([]byte("Bad Request! " + msg))
+	sendStatusText(w, http.StatusBadRequest, msg)
+}
+
+func SendCreated(w http.ResponseWriter, payload interface{}) {
+	SendJsonResponseWithStatus(w, http.StatusCreated, payload)
+}
+
+func SendNoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func SendUnauthorized(w http.ResponseWriter, msg string) {
+	sendStatusText(w, http.StatusUnauthorized, msg)
+}
+
+func SendForbidden(w http.ResponseWriter, msg string) {
+	sendStatusText(w, http.StatusForbidden, msg)
+}
+
+func SendNotFound(w http.ResponseWriter, msg string) {
+	sendStatusText(w, http.StatusNotFound, msg)
+}
+
+func SendConflict(w http.ResponseWriter, msg string) {
+	sendStatusText(w, http.StatusConflict, msg)
+}
+
+// sendStatusText writes a plain text body like "Not Found! msg".
+func sendStatusText(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+	_, err := w.Write([]byte(http.StatusText(status) + "! " + msg))
 	if err != nil {
 		alerts.Send("Error writing the response", err)
 	}
 }
 
+func SendErrorResponse(w http.ResponseWriter, status int, code string, message string) {
+	SendJsonResponseWithStatus(w, status, ErrorResponse{
+		Error: ErrorDetail{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
+
 func HealthCheckHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	_, err := w.Write([]byte("OK"))
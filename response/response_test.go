@@ -0,0 +1,32 @@
+package response
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewResponseRoundTrip(t *testing.T) {
+	type item struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	body, err := json.Marshal(NewResponse("ok", item{ID: 1, Name: "first"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"message":"ok","payload":{"id":1,"name":"first"}}`
+	if string(body) != want {
+		t.Fatalf("body = %s, want %s", body, want)
+	}
+
+	var got struct {
+		Message string `json:"message"`
+		Payload item   `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Message != "ok" || got.Payload != (item{ID: 1, Name: "first"}) {
+		t.Errorf("decoded %+v", got)
+	}
+}
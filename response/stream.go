@@ -0,0 +1,38 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Miskamyasa/utils/alerts"
+)
+
+// StreamNDJSON writes every item received from items as one JSON line until
+// the channel is closed. Output is flushed whenever no further item is ready,
+// so clients see data as it arrives. Items that cannot be encoded are
+// reported and skipped. If writing fails, e.g. because the client went away,
+// it stops reading from items; producers should watch the request context.
+func StreamNDJSON(w http.ResponseWriter, items <-chan interface{}) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+
+	for item := range items {
+		line, err := json.Marshal(item)
+		if err != nil {
+			alerts.Send("Error encoding the stream item", err)
+			continue
+		}
+		_, err = w.Write(append(line, '\n'))
+		if err != nil {
+			alerts.Send("Error writing the response", err)
+			return
+		}
+		if flusher != nil && len(items) == 0 {
+			flusher.Flush()
+		}
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
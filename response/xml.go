@@ -0,0 +1,34 @@
+package response
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+
+	"github.com/Miskamyasa/utils/alerts"
+)
+
+func SendXML(w http.ResponseWriter, payload interface{}) {
+	SendXMLWithStatus(w, http.StatusOK, payload)
+}
+
+// SendXMLWithStatus sends payload as an XML document, starting with the XML
+// declaration. Like the JSON helpers it encodes into a buffer first, so an
+// encoding error results in a clean 500.
+func SendXMLWithStatus(w http.ResponseWriter, status int, payload interface{}) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	err := xml.NewEncoder(&buf).Encode(payload)
+	if err != nil {
+		alerts.Send("Error encoding the response", err)
+		SendInternalServerError(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		alerts.Send("Error writing the response", err)
+	}
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Miskamyasa/utils/middlewares"
+	"github.com/Miskamyasa/utils/response"
+)
+
+// DefaultHealthPath is where NewRouter registers the health check.
+const DefaultHealthPath = "/health"
+
+type routerConfig struct {
+	recovery   bool
+	logging    bool
+	requestID  bool
+	auth       func(http.Handler) http.Handler
+	cache      func(http.Handler) http.Handler
+	extra      []func(http.Handler) http.Handler
+	healthPath string
+	health     http.Handler
+}
+
+// RouterOption changes the middleware stack built by NewRouter.
+type RouterOption func(*routerConfig)
+
+// WithoutRecovery leaves out RecoveryMiddleware.
+func WithoutRecovery() RouterOption {
+	return func(cfg *routerConfig) { cfg.recovery = false }
+}
+
+// WithoutLogging leaves out LoggingMiddleware.
+func WithoutLogging() RouterOption {
+	return func(cfg *routerConfig) { cfg.logging = false }
+}
+
+// WithoutRequestID leaves out RequestIDMiddleware.
+func WithoutRequestID() RouterOption {
+	return func(cfg *routerConfig) { cfg.requestID = false }
+}
+
+// WithAuth protects every route except the health check with
+// NewAuthMiddleware(cfg).
+func WithAuth(cfg middlewares.AuthConfig) RouterOption {
+	return func(rc *routerConfig) { rc.auth = middlewares.NewAuthMiddleware(cfg) }
+}
+
+// WithCache caches GET responses of every route except the health check for
+// ttl.
+func WithCache(ttl time.Duration) RouterOption {
+	return func(cfg *routerConfig) { cfg.cache = middlewares.NewCacheMiddleware(ttl) }
+}
+
+// WithMiddleware adds middlewares that run after the standard ones, right
+// before the routes.
+func WithMiddleware(mws ...func(http.Handler) http.Handler) RouterOption {
+	return func(cfg *routerConfig) { cfg.extra = append(cfg.extra, mws...) }
+}
+
+// WithHealthCheck replaces the default health handler and its path. An empty
+// path disables the health check.
+func WithHealthCheck(path string, handler http.Handler) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.healthPath = path
+		cfg.health = handler
+	}
+}
+
+// Router is an http.ServeMux served behind a standard middleware stack.
+// Register routes on it as on any ServeMux.
+type Router struct {
+	*http.ServeMux
+	handler http.Handler
+}
+
+// NewRouter returns a Router wrapped in request ID, logging and recovery
+// middlewares, in that order from the outside in, with a health check at
+// DefaultHealthPath. Auth and cache middlewares are added with WithAuth and
+// WithCache; they do not apply to the health check, so load balancers can
+// reach it without credentials.
+func NewRouter(opts ...RouterOption) *Router {
+	cfg := routerConfig{
+		recovery:   true,
+		logging:    true,
+		requestID:  true,
+		healthPath: DefaultHealthPath,
+		health:     http.HandlerFunc(response.HealthCheckHandler),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var base []func(http.Handler) http.Handler
+	if cfg.requestID {
+		base = append(base, middlewares.RequestIDMiddleware)
+	}
+	if cfg.logging {
+		base = append(base, middlewares.LoggingMiddleware)
+	}
+	if cfg.recovery {
+		base = append(base, middlewares.RecoveryMiddleware)
+	}
+
+	var routes []func(http.Handler) http.Handler
+	if cfg.auth != nil {
+		routes = append(routes, cfg.auth)
+	}
+	if cfg.cache != nil {
+		routes = append(routes, cfg.cache)
+	}
+	routes = append(routes, cfg.extra...)
+
+	r := &Router{ServeMux: http.NewServeMux()}
+	top := http.NewServeMux()
+	top.Handle("/", middlewares.Chain(routes...)(r.ServeMux))
+	if cfg.healthPath != "" && cfg.health != nil {
+		top.Handle("GET "+cfg.healthPath, cfg.health)
+	}
+	r.handler = middlewares.Chain(base...)(top)
+	return r
+}
+
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.handler.ServeHTTP(w, req)
+}